@@ -8,7 +8,10 @@ package command
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"io"
+	"os/exec"
 	"strings"
 	"time"
 
@@ -21,6 +24,7 @@ import (
 
 // Command represents a to-be-executed shell command.
 type Command struct {
+	ctx                 context.Context
 	opt                 *option.Option
 	args                []string
 	stdout              io.Writer
@@ -29,6 +33,8 @@ type Command struct {
 	shouldWait          bool
 	shouldCheckExitCode bool
 	shouldSucceed       bool
+	retry               *retryPolicy
+	recorder            *recorder
 }
 
 // New creates a command with the default configuration.
@@ -101,18 +107,36 @@ func (c *Command) WithStdin(stdin io.Reader) *Command {
 	return c
 }
 
+// WithContext ties the lifetime of the process to ctx, which is usually the SpecContext of the running spec.
+//
+// The process is started in a new process group. When ctx is done (e.g., the spec is interrupted or times out),
+// the process is killed together with all of its children, even if the session is not waited for (see WithoutWait).
+// If Run is still waiting for the session at that time, the failure includes the stderr of the process.
+func (c *Command) WithContext(ctx context.Context) *Command {
+	c.ctx = ctx
+	return c
+}
+
 // Run starts a session and waits for it to finish.
 // It's behavior can be modified by using other Command methods.
 // It returns the ended session for further assertions.
+//
+// If the subject runs on another host (see option.WithTransport), the files and directories under the home directory
+// that are referenced by the args (e.g., a build context created by ffs.CreateBuildContext) are staged to it first.
+// If the option records a transcript (see option.WithTranscript), the session is recorded to it after it exits.
+// If labels are set in the option (see option.SetLabel),
+// they are added to the commands that create containers, images, volumes or networks (e.g., "run" and "volume create").
 func (c *Command) Run() *gexec.Session {
-	cmd := c.opt.NewCmd(c.args...)
-	cmd.Stdin = c.stdin
-	session, err := gexec.Start(cmd, c.stdout, ginkgo.GinkgoWriter)
-	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	session := c.start()
 	if !c.shouldWait {
 		return session
 	}
-	session.Wait(c.timeout)
+	if c.retry != nil {
+		session = c.runWithRetry(session)
+	} else {
+		c.wait(session)
+	}
+	c.waitForRecord(session)
 
 	if !c.shouldCheckExitCode {
 		return session
@@ -127,6 +151,54 @@ func (c *Command) Run() *gexec.Session {
 	return session
 }
 
+func (c *Command) start() *gexec.Session {
+	args := withLabels(c.opt, c.args)
+	stage(c.opt, args)
+	cmd := c.opt.NewCmd(args...)
+	cmd.Stdin = c.stdin
+	if c.ctx != nil {
+		setProcessGroup(cmd)
+	}
+	c.recorder = c.newRecorder(cmd)
+	session, err := gexec.Start(cmd, c.stdout, ginkgo.GinkgoWriter)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	if c.recorder != nil {
+		c.recorder.recordOnExit(cmd, args, session)
+	}
+	if c.ctx != nil {
+		c.killOnDone(cmd, session)
+	}
+	return session
+}
+
+func (c *Command) wait(session *gexec.Session) {
+	if c.ctx != nil {
+		gomega.Eventually(session).WithContext(c.ctx).WithTimeout(c.timeout).Should(gexec.Exit(), func() string {
+			return fmt.Sprintf("%q did not exit, stderr:\n%s", strings.Join(c.args, " "), session.Err.Contents())
+		})
+	} else {
+		session.Wait(c.timeout)
+	}
+}
+
+// killOnDone kills the process group of cmd when the context of c is done, or when the session times out.
+func (c *Command) killOnDone(cmd *exec.Cmd, session *gexec.Session) {
+	ctx, cancel := c.ctx, context.CancelFunc(func() {})
+	if c.shouldWait {
+		ctx, cancel = context.WithTimeout(c.ctx, c.timeout)
+	}
+	go func() {
+		defer cancel()
+		select {
+		case <-session.Exited:
+		case <-ctx.Done():
+			if err := killProcessGroup(cmd); err != nil {
+				ginkgo.GinkgoWriter.Printf("Failed to kill the process group of %q: %v\n", strings.Join(c.args, " "), err)
+			}
+		}
+	}()
+}
+
 // Run starts a session, waits for it to finish, ensures the exit code to be 0,
 // and returns the ended session to be used for assertions.
 func Run(o *option.Option, args ...string) *gexec.Session {
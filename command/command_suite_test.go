@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/runfinch/common-tests/fake"
+	"github.com/runfinch/common-tests/option"
+)
+
+var fakeSubject string
+
+func TestCommand(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Command Suite")
+}
+
+var _ = ginkgo.SynchronizedBeforeSuite(func() []byte {
+	path, err := gexec.Build("github.com/runfinch/common-tests/fake/cmd/fake-subject")
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	return []byte(path)
+}, func(path []byte) {
+	fakeSubject = string(path)
+})
+
+var _ = ginkgo.SynchronizedAfterSuite(func() {}, func() {
+	gexec.CleanupBuildArtifacts()
+})
+
+// newFakeOption returns an option whose subject is the fake subject driven by scenario,
+// and the path to the log that the invocations are recorded in.
+func newFakeOption(scenario string, modifiers ...option.Modifier) (*option.Option, string) {
+	dir := ginkgo.GinkgoT().TempDir()
+	log := filepath.Join(dir, "invocations.jsonl")
+	path := filepath.Join(dir, "scenario.yaml")
+	gomega.Expect(os.WriteFile(path, []byte("log: "+log+"\n"+scenario), 0o600)).Should(gomega.Succeed())
+	o, err := option.New([]string{fakeSubject, path}, modifiers...)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	return o, log
+}
+
+func invocations(log string) [][]string {
+	got, err := fake.Invocations(log)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	return got
+}
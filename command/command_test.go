@@ -0,0 +1,233 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/ffs"
+	"github.com/runfinch/common-tests/option"
+)
+
+var _ = ginkgo.Describe("Command", func() {
+	ginkgo.It("should return the stdout of a successful command", func() {
+		o, _ := newFakeOption(`
+rules:
+  - args: ^images -q$
+    stdout: "  id1\nid2\n"
+`)
+		gomega.Expect(command.StdoutStr(o, "images", "-q")).Should(gomega.Equal("id1\nid2"))
+		gomega.Expect(command.StdoutAsLines(o, "images", "-q")).Should(gomega.Equal([]string{"  id1", "id2"}))
+	})
+
+	ginkgo.It("should return the stderr of a failed command", func() {
+		o, _ := newFakeOption(`
+rules:
+  - args: ^pull ne-repo:ne-tag$
+    stderr: not found
+    exitCode: 1
+`)
+		session := command.RunWithoutSuccessfulExit(o, "pull", "ne-repo:ne-tag")
+		gomega.Expect(session.ExitCode()).Should(gomega.Equal(1))
+		gomega.Expect(string(session.Err.Contents())).Should(gomega.Equal("not found"))
+	})
+
+	ginkgo.It("should fail if a command that is expected to succeed fails", func() {
+		o, _ := newFakeOption("")
+		failures := gomega.InterceptGomegaFailures(func() {
+			command.Run(o, "images")
+		})
+		gomega.Expect(failures).ShouldNot(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should fail if a command that is expected to fail succeeds", func() {
+		o, _ := newFakeOption("default: {}\n")
+		failures := gomega.InterceptGomegaFailures(func() {
+			command.RunWithoutSuccessfulExit(o, "images")
+		})
+		gomega.Expect(failures).ShouldNot(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should not check the exit code with WithoutCheckingExitCode", func() {
+		o, _ := newFakeOption("default: {exitCode: 3}\n")
+		session := command.New(o, "images").WithoutCheckingExitCode().Run()
+		gomega.Expect(session.ExitCode()).Should(gomega.Equal(3))
+	})
+
+	ginkgo.It("should pipe stdin to the command", func() {
+		o, _ := newFakeOption(`
+rules:
+  - args: ^exec -i ctr cat$
+    echoStdin: true
+`)
+		out := command.New(o, "exec", "-i", "ctr", "cat").WithStdin(gbytes.BufferWithBytes([]byte("hello"))).Run().Out.Contents()
+		gomega.Expect(string(out)).Should(gomega.Equal("hello"))
+	})
+
+	ginkgo.It("should fail if the command does not finish before the timeout", func() {
+		o, _ := newFakeOption("default: {delay: 2s}\n")
+		failures := gomega.InterceptGomegaFailures(func() {
+			command.New(o, "images").WithTimeout(200 * time.Millisecond).Run()
+		})
+		gomega.Expect(failures).ShouldNot(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should not wait for the command with WithoutWait", func() {
+		o, _ := newFakeOption("default: {delay: 1s}\n")
+		session := command.RunWithoutWait(o, "events")
+		gomega.Expect(session.ExitCode()).Should(gomega.Equal(-1))
+		gomega.Eventually(session).WithTimeout(5 * time.Second).Should(gexec.Exit(0))
+	})
+
+	ginkgo.It("should kill the command when the context is done", func() {
+		o, _ := newFakeOption("default: {delay: 1m}\n")
+		ctx, cancel := context.WithCancel(context.Background())
+		session := command.New(o, "events").WithContext(ctx).WithoutWait().Run()
+		cancel()
+		gomega.Eventually(session).WithTimeout(5 * time.Second).Should(gexec.Exit())
+	})
+
+	ginkgo.It("should fail with the stderr if the context is done before the command finishes", func() {
+		o, _ := newFakeOption("default: {delay: 1m}\n")
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		var session *gexec.Session
+		failures := gomega.InterceptGomegaFailures(func() {
+			session = command.New(o, "images").WithContext(ctx).WithTimeout(time.Minute).Run()
+		})
+		gomega.Expect(failures).Should(gomega.ContainElement(gomega.ContainSubstring(`"images" did not exit, stderr:`)))
+		gomega.Eventually(session).WithTimeout(5 * time.Second).Should(gexec.Exit())
+	})
+
+	ginkgo.It("should kill the command if it times out", func() {
+		o, _ := newFakeOption("default: {delay: 1m}\n")
+		var session *gexec.Session
+		failures := gomega.InterceptGomegaFailures(func() {
+			session = command.New(o, "images").WithContext(context.Background()).WithTimeout(200 * time.Millisecond).Run()
+		})
+		gomega.Expect(failures).ShouldNot(gomega.BeEmpty())
+		gomega.Eventually(session).WithTimeout(5 * time.Second).Should(gexec.Exit())
+	})
+})
+
+var _ = ginkgo.Describe("Snapshot", func() {
+	ginkgo.It("should list the objects that only exist in the later snapshot", func() {
+		o, _ := newFakeOption(`
+rules:
+  - args: ^ps
+    stdout: "ctr1\n"
+  - args: ^images --all --quiet$
+    stdout: "img1\n"
+  - args: ^volume ls
+  - args: ^network ls
+    stdout: "bridge\n"
+`)
+		before := command.TakeSnapshot(o)
+		gomega.Expect(before.Diff(before).IsEmpty()).Should(gomega.BeTrue())
+
+		later := command.Snapshot{
+			ContainerIDs: []string{"ctr1", "ctr2"},
+			ImageIDs:     []string{"img1"},
+			VolumeNames:  []string{"vol"},
+			NetworkNames: []string{"bridge"},
+		}
+		leaked := before.Diff(later)
+		gomega.Expect(leaked.IsEmpty()).Should(gomega.BeFalse())
+		gomega.Expect(leaked).Should(gomega.Equal(command.Snapshot{ContainerIDs: []string{"ctr2"}, VolumeNames: []string{"vol"}}))
+		gomega.Expect(leaked.String()).Should(gomega.Equal("containers: ctr2\nvolumes: vol"))
+	})
+
+	ginkgo.It("should remove the objects in the snapshot", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^(rm|rmi|volume rm|network rm)
+`)
+		command.RemoveSnapshot(o, command.Snapshot{
+			ContainerIDs: []string{"ctr"},
+			ImageIDs:     []string{"img"},
+			VolumeNames:  []string{"vol"},
+			NetworkNames: []string{"bridge", "net"},
+		})
+		gomega.Expect(invocations(log)).Should(gomega.Equal([][]string{
+			{"rm", "--force", "ctr"},
+			{"rmi", "--force", "img"},
+			{"volume", "rm", "--force", "vol"},
+			{"network", "rm", "net"},
+		}))
+	})
+})
+
+var _ = ginkgo.Describe("Labels", func() {
+	ginkgo.It("should add the labels to the commands that create objects", func() {
+		o, log := newFakeOption("default: {}\n")
+		o.SetLabel("ns", "p1")
+		command.Run(o, "run", "--name", "ctr", "alpine")
+		command.Run(o, "volume", "create", "vol")
+		command.Run(o, "network", "create", "net")
+		command.Run(o, "volume", "ls")
+		command.Run(o, "ps")
+		gomega.Expect(invocations(log)).Should(gomega.Equal([][]string{
+			{"run", "--label", "ns=p1", "--name", "ctr", "alpine"},
+			{"volume", "create", "--label", "ns=p1", "vol"},
+			{"network", "create", "--label", "ns=p1", "net"},
+			{"volume", "ls"},
+			{"ps"},
+		}))
+	})
+})
+
+// stageRecorder is a transport that runs the subject locally and records the staged and unstaged paths.
+type stageRecorder struct {
+	option.LocalTransport
+	staged   []string
+	unstaged []string
+}
+
+func (r *stageRecorder) Stage(p string) error {
+	r.staged = append(r.staged, p)
+	return nil
+}
+
+func (r *stageRecorder) Unstage(p string) error {
+	r.unstaged = append(r.unstaged, p)
+	return nil
+}
+
+var _ = ginkgo.Describe("Staging", ginkgo.Ordered, func() {
+	r := &stageRecorder{}
+	var o *option.Option
+
+	ginkgo.BeforeAll(func() {
+		o, _ = newFakeOption("default: {}\n", option.WithTransport(r))
+	})
+
+	ginkgo.It("should stage the paths under the home directory that are referenced by the args once", func() {
+		buildContext := ffs.CreateBuildContext("FROM alpine")
+		ginkgo.DeferCleanup(os.RemoveAll, buildContext)
+		envFile := ffs.CreateTempFile("env", "FOO=bar")
+		ginkgo.DeferCleanup(os.RemoveAll, filepath.Dir(envFile))
+		volumeDir := ffs.CreateTempDir("finch-test")
+		ginkgo.DeferCleanup(os.RemoveAll, volumeDir)
+
+		command.Run(o, "build", buildContext)
+		command.Run(o, "run", "--env-file="+envFile, "-v", volumeDir+":/data", "alpine", "/bin/sh", "-c", "true")
+		command.Run(o, "build", buildContext)
+		gomega.Expect(r.staged).Should(gomega.Equal([]string{buildContext, filepath.Dir(envFile), volumeDir}))
+		gomega.Expect(r.unstaged).Should(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should unstage the paths after the spec that staged them", func() {
+		gomega.Expect(r.unstaged).Should(gomega.Equal(r.staged))
+		gomega.Expect(o.StagedPaths()).Should(gomega.BeEmpty())
+	})
+})
@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"slices"
+
+	"github.com/runfinch/common-tests/option"
+)
+
+// labelledSubcommands are the subcommands that create objects which the labels of an option are added to.
+var labelledSubcommands = [][]string{
+	{"run"},
+	{"create"},
+	{"build"},
+	{"container", "run"},
+	{"container", "create"},
+	{"image", "build"},
+	{"volume", "create"},
+	{"network", "create"},
+}
+
+// withLabels adds a --label flag right after the subcommand for each label of o
+// if args invoke one of the labelledSubcommands.
+func withLabels(o *option.Option, args []string) []string {
+	labels := o.Labels()
+	if len(labels) == 0 {
+		return args
+	}
+	for _, sub := range labelledSubcommands {
+		if len(args) < len(sub) || !slices.Equal(args[:len(sub)], sub) {
+			continue
+		}
+		res := slices.Clone(args[:len(sub)])
+		for _, label := range labels {
+			res = append(res, "--label", label)
+		}
+		return append(res, args[len(sub):]...)
+	}
+	return args
+}
@@ -4,13 +4,22 @@
 package command
 
 import (
+	"strings"
+
 	"github.com/onsi/ginkgo/v2"
 
 	"github.com/runfinch/common-tests/option"
 )
 
 // RemoveAll removes all containers and images in the testing environment specified by o.
+//
+// The objects protected by o (see option.Protect) are not removed.
+// If o scopes the cleanup to a label (see option.WithLabelScopedCleanup), only the objects with that label are removed.
+// Like the other cleanup functions in this package, it does nothing while o suspends the cleanup (see option.Option.SuspendCleanup).
 func RemoveAll(o *option.Option) {
+	if suspended(o) {
+		return
+	}
 	RemoveContainers(o)
 	RemoveImages(o)
 	RemoveVolumes(o)
@@ -19,13 +28,26 @@ func RemoveAll(o *option.Option) {
 
 // RemoveContainers removes all containers in the testing environment specified by o.
 func RemoveContainers(o *option.Option) {
-	allIDs := GetAllContainerIDs(o)
-	var ids []string
-	for _, id := range allIDs {
-		if id != localRegistryContainerID {
-			ids = append(ids, id)
-		}
+	if suspended(o) {
+		return
 	}
+	if label := o.CleanupLabel(); label != "" {
+		RemoveContainersWithLabel(o, label)
+		return
+	}
+	removeContainers(o, GetAllContainerIDs(o))
+}
+
+// RemoveContainersWithLabel removes all containers that have the label specified in the format of key=value.
+func RemoveContainersWithLabel(o *option.Option, label string) {
+	if suspended(o) {
+		return
+	}
+	removeContainers(o, StdoutAsLines(o, "ps", "--all", "--quiet", "--no-trunc", "--filter", "label="+label))
+}
+
+func removeContainers(o *option.Option, allIDs []string) {
+	ids := unprotected(o, allIDs, localRegistryContainerID)
 	if len(ids) == 0 {
 		ginkgo.GinkgoWriter.Println("No containers to be removed")
 		return
@@ -37,36 +59,57 @@ func RemoveContainers(o *option.Option) {
 
 // RemoveVolumes removes all unused local volumes in the testing environment specified by o.
 func RemoveVolumes(o *option.Option) {
-	volumes := GetAllVolumeNames(o)
+	if suspended(o) {
+		return
+	}
+	if label := o.CleanupLabel(); label != "" {
+		RemoveVolumesWithLabel(o, label)
+		return
+	}
+	allVolumes := GetAllVolumeNames(o)
+	volumes := unprotected(o, allVolumes)
 	if len(volumes) == 0 {
 		ginkgo.GinkgoWriter.Println("No volumes to be removed")
 		return
 	}
+	// "volume prune" would remove the protected volumes too.
+	if len(volumes) < len(allVolumes) {
+		Run(o, append([]string{"volume", "rm", "--force"}, volumes...)...)
+		return
+	}
 	Run(o, "volume", "prune", "--force", "--all")
 }
 
+// RemoveVolumesWithLabel removes all volumes that have the label specified in the format of key=value.
+func RemoveVolumesWithLabel(o *option.Option, label string) {
+	if suspended(o) {
+		return
+	}
+	volumes := unprotected(o, StdoutAsLines(o, "volume", "ls", "--quiet", "--filter", "label="+label))
+	if len(volumes) == 0 {
+		ginkgo.GinkgoWriter.Println("No volumes to be removed")
+		return
+	}
+	Run(o, append([]string{"volume", "rm", "--force"}, volumes...)...)
+}
+
 // RemoveImages removes all container images in the testing environment specified by o.
 func RemoveImages(o *option.Option) {
-	allIDs := GetAllImageIDs(o)
-	var ids []string
-	for _, id := range allIDs {
-		if id != localRegistryImageID {
-			ids = append(ids, id)
-		}
+	if suspended(o) {
+		return
+	}
+	if label := o.CleanupLabel(); label != "" {
+		RemoveImagesWithLabel(o, label)
+		return
 	}
+	ids := unprotected(o, GetAllImageIDs(o), localRegistryImageID)
 	if removedAllImages(ids) {
 		return
 	}
 	args := append([]string{"rmi", "--force"}, ids...)
 	Run(o, args...)
 
-	allNames := GetAllImageNames(o)
-	var names []string
-	for _, name := range allNames {
-		if name != localRegistryImageName {
-			names = append(names, name)
-		}
-	}
+	names := unprotected(o, GetAllImageNames(o), localRegistryImageName)
 	if removedAllImages(names) {
 		return
 	}
@@ -74,13 +117,43 @@ func RemoveImages(o *option.Option) {
 	Run(o, args...)
 }
 
+// RemoveImagesWithLabel removes all images that have the label specified in the format of key=value.
+func RemoveImagesWithLabel(o *option.Option, label string) {
+	if suspended(o) {
+		return
+	}
+	ids := unprotected(o, StdoutAsLines(o, "images", "--quiet", "--filter", "label="+label), localRegistryImageID)
+	if removedAllImages(ids) {
+		return
+	}
+	Run(o, append([]string{"rmi", "--force"}, ids...)...)
+}
+
 // RemoveNetworks removes all networks in the testing environment specified by o.
 // TODO: use "network prune" after upgrading nerdctl to v0.23.
 func RemoveNetworks(o *option.Option) {
+	if suspended(o) {
+		return
+	}
+	if label := o.CleanupLabel(); label != "" {
+		RemoveNetworksWithLabel(o, label)
+		return
+	}
+	removeNetworks(o, GetAllNetworkNames(o))
+}
+
+// RemoveNetworksWithLabel removes all networks that have the label specified in the format of key=value.
+func RemoveNetworksWithLabel(o *option.Option, label string) {
+	if suspended(o) {
+		return
+	}
+	removeNetworks(o, StdoutAsLines(o, "network", "ls", "--filter", "label="+label, "--format", "{{.Name}}"))
+}
+
+func removeNetworks(o *option.Option, networks []string) {
 	defaultNetworks := []string{"bridge", "host", "none"}
-	networks := GetAllNetworkNames(o)
 	var customNetworks []string
-	for _, n := range networks {
+	for _, n := range unprotected(o, networks) {
 		if !contains(defaultNetworks, n) {
 			customNetworks = append(customNetworks, n)
 		}
@@ -95,6 +168,69 @@ func RemoveNetworks(o *option.Option) {
 	Run(o, args...)
 }
 
+// RemoveAllWithLabel removes all containers, images, volumes and networks that have the label specified in the format of key=value.
+//
+// Unlike RemoveAll, it only touches the objects created by the caller (see option.SetLabel),
+// so it can be used when multiple test processes share the same testing environment.
+func RemoveAllWithLabel(o *option.Option, label string) {
+	if suspended(o) {
+		return
+	}
+	RemoveContainersWithLabel(o, label)
+	RemoveImagesWithLabel(o, label)
+	RemoveVolumesWithLabel(o, label)
+	RemoveNetworksWithLabel(o, label)
+}
+
+// RemoveAllWithNameContaining removes all containers, images, volumes and networks whose names contain s.
+//
+// It complements RemoveAllWithLabel for the objects that are not labelled when they are created,
+// e.g., the volumes created by "run -v" and the images created by "tag" or "commit".
+func RemoveAllWithNameContaining(o *option.Option, s string) {
+	if suspended(o) {
+		return
+	}
+	removeContainers(o, containing(StdoutAsLines(o, "ps", "--all", "--format", "{{.Names}}"), s))
+	if images := unprotected(o, containing(GetAllImageNames(o), s), localRegistryImageName); !removedAllImages(images) {
+		Run(o, append([]string{"rmi", "--force"}, images...)...)
+	}
+	if volumes := unprotected(o, containing(GetAllVolumeNames(o), s)); len(volumes) > 0 {
+		Run(o, append([]string{"volume", "rm", "--force"}, volumes...)...)
+	}
+	removeNetworks(o, containing(GetAllNetworkNames(o), s))
+}
+
+// suspended returns true if o suspends the cleanup (see option.Option.SuspendCleanup).
+func suspended(o *option.Option) bool {
+	if o.IsCleanupSuspended() {
+		ginkgo.GinkgoWriter.Println("The cleanup is suspended")
+		return true
+	}
+	return false
+}
+
+// containing returns the strings in strs that contain s.
+func containing(strs []string, s string) []string {
+	var res []string
+	for _, str := range strs {
+		if strings.Contains(str, s) {
+			res = append(res, str)
+		}
+	}
+	return res
+}
+
+// unprotected returns the objects that are neither protected by o nor in excluded.
+func unprotected(o *option.Option, objs []string, excluded ...string) []string {
+	var res []string
+	for _, obj := range objs {
+		if !o.IsProtected(obj) && !contains(excluded, obj) {
+			res = append(res, obj)
+		}
+	}
+	return res
+}
+
 func contains(strs []string, target string) bool {
 	for _, str := range strs {
 		if str == target {
@@ -0,0 +1,192 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+)
+
+var _ = ginkgo.Describe("Remove", func() {
+	ginkgo.AfterEach(func() {
+		command.SetLocalRegistryContainerID("")
+		command.SetLocalRegistryImageID("")
+		command.SetLocalRegistryImageName("")
+	})
+
+	ginkgo.It("should remove all containers except the local registry", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^ps --all --quiet --no-trunc$
+    stdout: "ctr1\nregistry\nctr2\n"
+  - args: ^rm --force
+`)
+		command.SetLocalRegistryContainerID("registry")
+		command.RemoveContainers(o)
+		gomega.Expect(invocations(log)).Should(gomega.ContainElement([]string{"rm", "--force", "ctr1", "ctr2"}))
+	})
+
+	ginkgo.It("should not remove any container if there is none", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^ps --all --quiet --no-trunc$
+`)
+		command.RemoveContainers(o)
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should remove all images except the local registry image", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^images --all --quiet$
+    stdout: "img1\nregistry-img\n"
+  - args: ^images --all --format
+    stdout: "foo:latest\nregistry:latest\n"
+  - args: ^rmi --force
+`)
+		command.SetLocalRegistryImageID("registry-img")
+		command.SetLocalRegistryImageName("registry:latest")
+		command.RemoveImages(o)
+		gomega.Expect(invocations(log)).Should(gomega.ContainElements(
+			[]string{"rmi", "--force", "img1"},
+			[]string{"rmi", "--force", "foo:latest"},
+		))
+	})
+
+	ginkgo.It("should only remove the custom networks", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^network ls --format
+    stdout: "bridge\nhost\nnone\ntest-network\n"
+  - args: ^network rm
+`)
+		command.RemoveNetworks(o)
+		gomega.Expect(invocations(log)).Should(gomega.ContainElement([]string{"network", "rm", "test-network"}))
+	})
+
+	ginkgo.It("should prune the volumes only if there is any", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^volume ls --quiet$
+    stdout: "vol\n"
+  - args: ^volume prune --force --all$
+`)
+		command.RemoveVolumes(o)
+		gomega.Expect(invocations(log)).Should(gomega.ContainElement([]string{"volume", "prune", "--force", "--all"}))
+	})
+})
+
+var _ = ginkgo.Describe("RemoveAllWithLabel", func() {
+	ginkgo.It("should only remove the objects with the label", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^ps --all --quiet --no-trunc --filter label=ns=p1$
+    stdout: "ctr\n"
+  - args: ^images --quiet --filter label=ns=p1$
+  - args: ^volume ls --quiet --filter label=ns=p1$
+    stdout: "vol\n"
+  - args: ^network ls --filter label=ns=p1
+  - args: ^(rm|volume rm)
+`)
+		command.RemoveAllWithLabel(o, "ns=p1")
+		got := invocations(log)
+		gomega.Expect(got).Should(gomega.ContainElements(
+			[]string{"rm", "--force", "ctr"},
+			[]string{"volume", "rm", "--force", "vol"},
+		))
+		gomega.Expect(got).Should(gomega.HaveLen(6))
+	})
+})
+
+var _ = ginkgo.Describe("RemoveAllWithNameContaining", func() {
+	ginkgo.It("should only remove the objects whose names contain the string", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^ps --all --format
+    stdout: "ctr-p1\nctr-p2\n"
+  - args: ^images --all --format
+    stdout: "img-p1:tag\nimg-p2:tag\n"
+  - args: ^volume ls --quiet$
+    stdout: "vol-p1\nvol-p2\n"
+  - args: ^network ls --format
+    stdout: "bridge\nnet-p1\nnet-p2\n"
+  - args: ^(rm|rmi|volume rm|network rm)
+`)
+		command.RemoveAllWithNameContaining(o, "p1")
+		gomega.Expect(invocations(log)).Should(gomega.Equal([][]string{
+			{"ps", "--all", "--format", "{{.Names}}"},
+			{"rm", "--force", "ctr-p1"},
+			{"images", "--all", "--format", "{{.Repository}}:{{.Tag}}"},
+			{"rmi", "--force", "img-p1:tag"},
+			{"volume", "ls", "--quiet"},
+			{"volume", "rm", "--force", "vol-p1"},
+			{"network", "ls", "--format", "{{.Name}}"},
+			{"network", "rm", "net-p1"},
+		}))
+	})
+})
+
+var _ = ginkgo.Describe("SuspendCleanup", func() {
+	ginkgo.It("should make the cleanup functions do nothing until the cleanup is resumed", func() {
+		o, log := newFakeOption("default: {}\n")
+		o.SuspendCleanup()
+		command.RemoveAll(o)
+		command.RemoveAllWithLabel(o, "ns=p1")
+		command.RemoveAllWithNameContaining(o, "p1")
+		command.RemoveSnapshot(o, command.Snapshot{ContainerIDs: []string{"ctr"}})
+		gomega.Expect(invocations(log)).Should(gomega.BeEmpty())
+
+		o.ResumeCleanup()
+		command.RemoveContainers(o)
+		gomega.Expect(invocations(log)).Should(gomega.Equal([][]string{{"ps", "--all", "--quiet", "--no-trunc"}}))
+	})
+})
+
+var _ = ginkgo.Describe("Protect", func() {
+	ginkgo.It("should not remove the protected objects", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^ps --all --quiet --no-trunc$
+    stdout: "ctr1\nmine\n"
+  - args: ^volume ls --quiet$
+    stdout: "vol\nmy-vol\n"
+  - args: ^network ls --format
+    stdout: "bridge\nmy-net\n"
+  - args: ^(rm|volume rm) --force
+`)
+		o.Protect("mine", "my-vol", "my-net")
+		command.RemoveContainers(o)
+		command.RemoveVolumes(o)
+		command.RemoveNetworks(o)
+		gomega.Expect(invocations(log)).Should(gomega.Equal([][]string{
+			{"ps", "--all", "--quiet", "--no-trunc"},
+			{"rm", "--force", "ctr1"},
+			{"volume", "ls", "--quiet"},
+			{"volume", "rm", "--force", "vol"},
+			{"network", "ls", "--format", "{{.Name}}"},
+		}))
+	})
+})
+
+var _ = ginkgo.Describe("Label-scoped cleanup", func() {
+	ginkgo.It("should only remove the objects with the suite label", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: --filter label=com.github.runfinch.common-tests=true
+    stdout: "obj\n"
+  - args: ^(rm|rmi|volume rm|network rm)
+`, option.WithLabelScopedCleanup())
+		command.RemoveAll(o)
+		gomega.Expect(invocations(log)).Should(gomega.ContainElements(
+			[]string{"rm", "--force", "obj"},
+			[]string{"rmi", "--force", "obj"},
+			[]string{"volume", "rm", "--force", "obj"},
+			[]string{"network", "rm", "obj"},
+		))
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(8))
+	})
+})
@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+// RetryIf reports whether an attempt should be retried based on its ended session.
+type RetryIf func(session *gexec.Session) bool
+
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+	retryIf  RetryIf
+}
+
+// WithRetry runs the command up to attempts times, waiting for backoff between the attempts,
+// until retryIf returns false for an attempt.
+//
+// If retryIf is nil, an attempt is retried if its exit code is not the expected one (see WithoutSuccessfulExit),
+// or not 0 if the exit code is not checked (see WithoutCheckingExitCode).
+// The timeout (see WithTimeout) applies to each attempt, and an attempt that times out is killed and always retried.
+// Every attempt is logged in GinkgoWriter, and the session of the last attempt is checked and returned by Run.
+//
+// The stdin (see WithStdin) is only consumed by the first attempt. WithRetry has no effect if the session is not waited for.
+func (c *Command) WithRetry(attempts int, backoff time.Duration, retryIf RetryIf) *Command {
+	c.retry = &retryPolicy{attempts: attempts, backoff: backoff, retryIf: retryIf}
+	return c
+}
+
+// Retry calls attempt up to attempts times, waiting for backoff between the calls, until it returns nil,
+// and returns the error of the last call.
+//
+// It applies the same policy as WithRetry to the checks that are not commands (e.g., an HTTP request),
+// and every failed call is logged in GinkgoWriter as what.
+func Retry(what string, attempts int, backoff time.Duration, attempt func() error) error {
+	var err error
+	for i := 1; ; i++ {
+		if err = attempt(); err == nil {
+			return nil
+		}
+		ginkgo.GinkgoWriter.Printf("Attempt %d/%d of %q failed: %v\n", i, attempts, what, err)
+		if i >= attempts {
+			return err
+		}
+		time.Sleep(backoff)
+	}
+}
+
+// RetryOnExitCode retries the attempts that exit with one of codes.
+func RetryOnExitCode(codes ...int) RetryIf {
+	return func(session *gexec.Session) bool {
+		return slices.Contains(codes, session.ExitCode())
+	}
+}
+
+// RetryOnStderr retries the attempts whose stderr contains substr, e.g., "TOOMANYREQUESTS" when pulling an image.
+func RetryOnStderr(substr string) RetryIf {
+	return func(session *gexec.Session) bool {
+		return strings.Contains(string(session.Err.Contents()), substr)
+	}
+}
+
+// RetryUntilStdout retries the attempts until the trimmed stdout equals want.
+// It's useful for waiting for an eventually consistent state, e.g., the status of a container.
+func RetryUntilStdout(want string) RetryIf {
+	return func(session *gexec.Session) bool {
+		return strings.TrimSpace(string(session.Out.Contents())) != want
+	}
+}
+
+func (c *Command) runWithRetry(session *gexec.Session) *gexec.Session {
+	cmd := strings.Join(c.args, " ")
+	for attempt := 1; ; attempt++ {
+		exited, cancelled := c.waitForAttempt(session)
+		switch {
+		case cancelled:
+			ginkgo.GinkgoWriter.Printf("Attempt %d/%d of %q was cancelled\n", attempt, c.retry.attempts, cmd)
+			gomega.Expect(c.ctx.Err()).ShouldNot(gomega.HaveOccurred(),
+				fmt.Sprintf("%q did not exit, stderr:\n%s", cmd, session.Err.Contents()))
+			return session
+		case !exited:
+			ginkgo.GinkgoWriter.Printf("Attempt %d/%d of %q timed out after %s\n", attempt, c.retry.attempts, cmd, c.timeout)
+		default:
+			ginkgo.GinkgoWriter.Printf("Attempt %d/%d of %q exited with code %d\n", attempt, c.retry.attempts, cmd, session.ExitCode())
+		}
+
+		if exited && !c.shouldRetry(session) {
+			return session
+		}
+		if attempt >= c.retry.attempts {
+			gomega.Expect(exited).Should(gomega.BeTrue(),
+				fmt.Sprintf("%q did not exit after %d attempts, stderr:\n%s", cmd, attempt, session.Err.Contents()))
+			return session
+		}
+		if !c.sleep(c.retry.backoff) {
+			return session
+		}
+		c.waitForRecord(session)
+		session = c.start()
+	}
+}
+
+// waitForAttempt waits for the session to exit within the timeout, and kills it otherwise.
+// cancelled is true if the context of c is done before the session exits.
+func (c *Command) waitForAttempt(session *gexec.Session) (exited, cancelled bool) {
+	var done <-chan struct{}
+	if c.ctx != nil {
+		done = c.ctx.Done()
+	}
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case <-session.Exited:
+		return true, false
+	case <-done:
+		cancelled = true
+	case <-timer.C:
+	}
+	session.Kill()
+	<-session.Exited
+	return false, cancelled
+}
+
+func (c *Command) shouldRetry(session *gexec.Session) bool {
+	if c.retry.retryIf != nil {
+		return c.retry.retryIf(session)
+	}
+	if c.shouldCheckExitCode && !c.shouldSucceed {
+		return session.ExitCode() == 0
+	}
+	return session.ExitCode() != 0
+}
+
+// sleep waits for d and returns false if the context of c is done before that.
+func (c *Command) sleep(d time.Duration) bool {
+	if c.ctx == nil {
+		time.Sleep(d)
+		return true
+	}
+	select {
+	case <-time.After(d):
+		return true
+	case <-c.ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+)
+
+var _ = ginkgo.Describe("Retry", func() {
+	ginkgo.It("should not retry a successful command", func() {
+		o, log := newFakeOption("default: {}\n")
+		command.New(o, "pull", "alpine").WithRetry(3, 0, nil).Run()
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should fail after all the attempts fail", func() {
+		o, log := newFakeOption("default: {exitCode: 1}\n")
+		failures := gomega.InterceptGomegaFailures(func() {
+			command.New(o, "pull", "alpine").WithRetry(3, 10*time.Millisecond, nil).Run()
+		})
+		gomega.Expect(failures).ShouldNot(gomega.BeEmpty())
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(3))
+	})
+
+	ginkgo.It("should retry a command that is expected to fail until it fails", func() {
+		o, log := newFakeOption("default: {}\n")
+		failures := gomega.InterceptGomegaFailures(func() {
+			command.New(o, "pull", "alpine").WithoutSuccessfulExit().WithRetry(2, 0, nil).Run()
+		})
+		gomega.Expect(failures).ShouldNot(gomega.BeEmpty())
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(2))
+	})
+
+	ginkgo.It("should only retry the attempts matching the predicate", func() {
+		o, log := newFakeOption(`
+rules:
+  - args: ^pull busy$
+    stderr: registry is busy
+    exitCode: 1
+  - args: ^pull missing$
+    stderr: not found
+    exitCode: 1
+`)
+		command.New(o, "pull", "missing").WithoutSuccessfulExit().WithRetry(3, 0, command.RetryOnStderr("busy")).Run()
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(1))
+		command.New(o, "pull", "busy").WithoutSuccessfulExit().WithRetry(3, 0, command.RetryOnStderr("busy")).Run()
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(4))
+	})
+
+	ginkgo.It("should retry the attempts until the stdout is the expected one", func() {
+		o, log := newFakeOption("default: {stdout: \"running\\n\"}\n")
+		session := command.New(o, "inspect").WithRetry(3, 0, command.RetryUntilStdout("paused")).Run()
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(3))
+		gomega.Expect(string(session.Out.Contents())).Should(gomega.Equal("running\n"))
+
+		command.New(o, "inspect").WithRetry(3, 0, command.RetryUntilStdout("running")).Run()
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(4))
+	})
+
+	ginkgo.It("should retry the attempts that exit with the codes", func() {
+		o, log := newFakeOption("default: {exitCode: 2}\n")
+		command.New(o, "pull", "alpine").WithoutSuccessfulExit().WithRetry(2, 0, command.RetryOnExitCode(1)).Run()
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(1))
+		command.New(o, "pull", "alpine").WithoutSuccessfulExit().WithRetry(2, 0, command.RetryOnExitCode(1, 2)).Run()
+		gomega.Expect(invocations(log)).Should(gomega.HaveLen(3))
+	})
+
+	ginkgo.It("should kill and retry the attempts that time out", func() {
+		o, _ := newFakeOption("default: {delay: 1m}\n")
+		start := time.Now()
+		failures := gomega.InterceptGomegaFailures(func() {
+			command.New(o, "pull", "alpine").WithTimeout(200*time.Millisecond).WithRetry(2, 0, nil).Run()
+		})
+		gomega.Expect(failures).Should(gomega.ContainElement(gomega.ContainSubstring("did not exit after 2 attempts")))
+		gomega.Expect(time.Since(start)).Should(gomega.BeNumerically("<", 5*time.Second))
+	})
+})
+
+var _ = ginkgo.Describe("Retry function", func() {
+	ginkgo.It("should call the attempt until it succeeds", func() {
+		calls := 0
+		err := command.Retry("check", 3, time.Millisecond, func() error {
+			calls++
+			if calls < 2 {
+				return errors.New("not ready")
+			}
+			return nil
+		})
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(calls).Should(gomega.Equal(2))
+	})
+
+	ginkgo.It("should return the last error after all the attempts fail", func() {
+		calls := 0
+		err := command.Retry("check", 3, time.Millisecond, func() error {
+			calls++
+			return errors.New("not ready")
+		})
+		gomega.Expect(err).Should(gomega.MatchError("not ready"))
+		gomega.Expect(calls).Should(gomega.Equal(3))
+	})
+})
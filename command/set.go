@@ -11,18 +11,24 @@ var (
 
 // SetLocalRegistryContainerID sets the ID for the local registry. Usually you don't need to invoke this function yourself.
 // For more details, see tests.SetupLocalRegistry.
+//
+// Deprecated: Use option.Protect instead.
 func SetLocalRegistryContainerID(id string) {
 	localRegistryContainerID = id
 }
 
 // SetLocalRegistryImageID sets the ID for local registry image. Usually you don't need to invoke this function yourself.
 // For more details, see tests.SetupLocalRegistry.
+//
+// Deprecated: Use option.Protect instead.
 func SetLocalRegistryImageID(id string) {
 	localRegistryImageID = id
 }
 
 // SetLocalRegistryImageName sets the local registry image name. Usually you don't need to invoke this function yourself.
 // For more details, see tests.SetupLocalRegistry.
+//
+// Deprecated: Use option.Protect instead.
 func SetLocalRegistryImageName(name string) {
 	localRegistryImageName = name
 }
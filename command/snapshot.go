@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/runfinch/common-tests/option"
+)
+
+// Snapshot records the objects that exist in the testing environment at a point in time.
+type Snapshot struct {
+	ContainerIDs []string
+	ImageIDs     []string
+	VolumeNames  []string
+	NetworkNames []string
+}
+
+// TakeSnapshot records the containers, images, volumes and networks in the testing environment specified by o.
+func TakeSnapshot(o *option.Option) Snapshot {
+	return Snapshot{
+		ContainerIDs: GetAllContainerIDs(o),
+		ImageIDs:     GetAllImageIDs(o),
+		VolumeNames:  GetAllVolumeNames(o),
+		NetworkNames: GetAllNetworkNames(o),
+	}
+}
+
+// Diff returns the objects that exist in later but not in s.
+func (s Snapshot) Diff(later Snapshot) Snapshot {
+	return Snapshot{
+		ContainerIDs: subtract(later.ContainerIDs, s.ContainerIDs),
+		ImageIDs:     subtract(later.ImageIDs, s.ImageIDs),
+		VolumeNames:  subtract(later.VolumeNames, s.VolumeNames),
+		NetworkNames: subtract(later.NetworkNames, s.NetworkNames),
+	}
+}
+
+// IsEmpty returns true if the snapshot does not contain any object.
+func (s Snapshot) IsEmpty() bool {
+	return len(s.ContainerIDs) == 0 && len(s.ImageIDs) == 0 && len(s.VolumeNames) == 0 && len(s.NetworkNames) == 0
+}
+
+// String returns a human-readable list of the objects in the snapshot.
+func (s Snapshot) String() string {
+	var sb strings.Builder
+	for _, kind := range []struct {
+		name string
+		objs []string
+	}{
+		{"containers", s.ContainerIDs},
+		{"images", s.ImageIDs},
+		{"volumes", s.VolumeNames},
+		{"networks", s.NetworkNames},
+	} {
+		if len(kind.objs) > 0 {
+			fmt.Fprintf(&sb, "%s: %s\n", kind.name, strings.Join(kind.objs, ", "))
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func subtract(strs []string, excluded []string) []string {
+	var res []string
+	for _, str := range strs {
+		if !contains(excluded, str) {
+			res = append(res, str)
+		}
+	}
+	return res
+}
+
+// RemoveSnapshot removes the objects recorded in s, e.g., the ones that a spec left behind (see Snapshot.Diff).
+func RemoveSnapshot(o *option.Option, s Snapshot) {
+	if suspended(o) {
+		return
+	}
+	removeContainers(o, s.ContainerIDs)
+	if ids := unprotected(o, s.ImageIDs, localRegistryImageID); !removedAllImages(ids) {
+		Run(o, append([]string{"rmi", "--force"}, ids...)...)
+	}
+	if volumes := unprotected(o, s.VolumeNames); len(volumes) > 0 {
+		Run(o, append([]string{"volume", "rm", "--force"}, volumes...)...)
+	}
+	removeNetworks(o, s.NetworkNames)
+}
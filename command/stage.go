@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/option"
+)
+
+// stage stages the local paths referenced by args for the subject (see option.Option.Stage).
+// Each path is staged once per spec because the staged copies are removed when the spec ends.
+func stage(o *option.Option, args []string) {
+	staged := len(o.StagedPaths()) > 0
+	gomega.Expect(o.Stage(stagedPaths(args)...)).Should(gomega.Succeed())
+	if !staged && len(o.StagedPaths()) > 0 {
+		ginkgo.DeferCleanup(o.Unstage)
+	}
+}
+
+// stagedPaths returns the local paths referenced by args that have to be staged for the subject (see option.Stage).
+//
+// Only the existing paths under the home directory, where the ffs helpers create the temporary files and directories,
+// are considered. They are found in the plain arguments (e.g., a build context), after a "key=" prefix
+// (e.g., --env-file=/path or source=/path in a --mount flag), and before a ":" (e.g., -v /path:/dst).
+// A file is staged together with the rest of its directory (e.g., the Dockerfile next to a compose file)
+// unless that directory is the home directory.
+func stagedPaths(args []string) []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, arg := range args {
+		for _, field := range strings.Split(arg, ",") {
+			if i := strings.LastIndex(field, "="); i >= 0 {
+				field = field[i+1:]
+			}
+			if i := strings.Index(field, ":"); i >= 0 {
+				field = field[:i]
+			}
+			p, ok := stagedPath(home, field)
+			if ok && !contains(paths, p) {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
+func stagedPath(home, p string) (string, bool) {
+	if !filepath.IsAbs(p) {
+		return "", false
+	}
+	p = filepath.Clean(p)
+	rel, err := filepath.Rel(home, p)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return "", false
+	}
+	if !info.IsDir() && filepath.Dir(p) != home {
+		return filepath.Dir(p), true
+	}
+	return p, true
+}
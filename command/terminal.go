@@ -0,0 +1,198 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/runfinch/common-tests/option"
+)
+
+// DefaultDetachKeys is the default key sequence to detach from a container, which can be overridden by --detach-keys.
+const DefaultDetachKeys = "ctrl-p,ctrl-q"
+
+// Terminal is a command running in a pseudo-terminal (PTY), e.g., `run -it` or `attach`.
+// It's created by Command.StartInTerminal.
+//
+// Everything that the command writes to the terminal is available in Out, e.g.,
+//
+//	gomega.Eventually(terminal).Should(gbytes.Say("prompt"))
+//
+// and it implements gexec.Exiter so that gexec.Exit can be used to wait for the command to exit, e.g.,
+//
+//	gomega.Eventually(terminal).Should(gexec.Exit(0))
+//
+// Note that a terminal echoes the keystrokes sent to it, and translates "\n" written by the command into "\r\n".
+type Terminal struct {
+	// Out is everything that the command writes to the terminal.
+	Out *gbytes.Buffer
+	// Exited is closed when the command exits.
+	Exited <-chan struct{}
+
+	cmd      *exec.Cmd
+	pty      *os.File
+	mu       sync.Mutex
+	exitCode int
+}
+
+// StartInTerminal starts the command in a new pseudo-terminal instead of a gexec session and returns without waiting
+// for it to exit. The configuration about waiting and the exit code (e.g., WithoutSuccessfulExit) does not apply,
+// and the command is neither retried (see WithRetry) nor recorded in the transcript (see option.WithTranscript).
+//
+// The spec is skipped if pseudo-terminals are not supported on the current platform (e.g., Windows),
+// or the transport of the option cannot forward a terminal to the subject (see option.TerminalTransport).
+//
+// The terminal is closed, and the command is killed if it's still running, when the spec ends.
+// If a context is specified (see WithContext), the command is also killed when it's done.
+func (c *Command) StartInTerminal() *Terminal {
+	args := withLabels(c.opt, c.args)
+	cmd, err := c.opt.NewTerminalCmd(args...)
+	if err != nil {
+		ginkgo.Skip(fmt.Sprintf("the subject cannot be run in a pseudo-terminal: %v", err))
+	}
+	stage(c.opt, args)
+	f, err := pty.Start(cmd)
+	if errors.Is(err, pty.ErrUnsupported) {
+		ginkgo.Skip(fmt.Sprintf("pseudo-terminals are not supported on %s", runtime.GOOS))
+	}
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), "failed to start %q in a pseudo-terminal", strings.Join(c.args, " "))
+
+	exited := make(chan struct{})
+	t := &Terminal{
+		Out:      gbytes.NewBuffer(),
+		Exited:   exited,
+		cmd:      cmd,
+		pty:      f,
+		exitCode: -1,
+	}
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		// Reading from the PTY fails (e.g., with EIO on Linux) after the command and its children exit.
+		_, _ = io.Copy(io.MultiWriter(t.Out, c.stdout), f)
+	}()
+	go func() {
+		defer close(exited)
+		err := cmd.Wait()
+		<-copied
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.exitCode = cmd.ProcessState.ExitCode()
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			ginkgo.GinkgoWriter.Printf("Failed to wait for %q: %v\n", strings.Join(c.args, " "), err)
+		}
+	}()
+	if c.ctx != nil {
+		go func() {
+			select {
+			case <-exited:
+			case <-c.ctx.Done():
+				t.kill()
+			}
+		}()
+	}
+	ginkgo.DeferCleanup(t.Close)
+	return t
+}
+
+// StartInTerminal starts the command in a new pseudo-terminal. See Command.StartInTerminal for more details.
+func StartInTerminal(o *option.Option, args ...string) *Terminal {
+	return New(o, args...).StartInTerminal()
+}
+
+// Buffer implements gbytes.BufferProvider so that gbytes.Say can be used against the terminal.
+func (t *Terminal) Buffer() *gbytes.Buffer {
+	return t.Out
+}
+
+// ExitCode implements gexec.Exiter. It returns -1 if the command is still running.
+func (t *Terminal) ExitCode() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.exitCode
+}
+
+// Send types the keys into the terminal as is, e.g., "\x03" for ctrl-c.
+func (t *Terminal) Send(keys string) {
+	_, err := t.pty.WriteString(keys)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+}
+
+// SendLine types the line into the terminal followed by the enter key.
+func (t *Terminal) SendLine(line string) {
+	t.Send(line + "\r")
+}
+
+// SendDetachKeys types the detach key sequence (e.g., DefaultDetachKeys) into the terminal. See DetachKeys for the format.
+func (t *Terminal) SendDetachKeys(spec string) {
+	keys, err := DetachKeys(spec)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	t.Send(string(keys))
+}
+
+// Resize changes the size of the terminal.
+func (t *Terminal) Resize(rows, cols uint16) {
+	gomega.Expect(pty.Setsize(t.pty, &pty.Winsize{Rows: rows, Cols: cols})).Should(gomega.Succeed())
+}
+
+// Wait waits for the command to exit within the timeout and returns the terminal for further assertions.
+func (t *Terminal) Wait(timeout time.Duration) *Terminal {
+	gomega.Eventually(t).WithTimeout(timeout).Should(gexec.Exit(), func() string {
+		return fmt.Sprintf("%q did not exit, output:\n%s", strings.Join(t.cmd.Args, " "), t.Out.Contents())
+	})
+	return t
+}
+
+// Close kills the command if it's still running, and closes the terminal.
+func (t *Terminal) Close() {
+	select {
+	case <-t.Exited:
+	default:
+		t.kill()
+		<-t.Exited
+	}
+	_ = t.pty.Close()
+}
+
+func (t *Terminal) kill() {
+	if err := killProcessGroup(t.cmd); err != nil {
+		ginkgo.GinkgoWriter.Printf("Failed to kill the process group of %q: %v\n", strings.Join(t.cmd.Args, " "), err)
+	}
+}
+
+// DetachKeys converts a detach key sequence in the format of --detach-keys (e.g., "ctrl-p,ctrl-q")
+// into the bytes that a terminal sends when the keys are typed.
+// Each key is either a single character (e.g., "a") or "ctrl-" followed by one of a-z, @, [, \, ], ^ and _.
+func DetachKeys(spec string) ([]byte, error) {
+	var keys []byte
+	for _, key := range strings.Split(spec, ",") {
+		ctrl, isCtrl := strings.CutPrefix(key, "ctrl-")
+		switch {
+		case isCtrl && len(ctrl) == 1 && ctrl[0] >= 'a' && ctrl[0] <= 'z':
+			keys = append(keys, ctrl[0]-'a'+1)
+		case isCtrl && len(ctrl) == 1 && strings.Contains(`@[\]^_`, ctrl):
+			keys = append(keys, ctrl[0]-'@')
+		case !isCtrl && len(key) == 1:
+			keys = append(keys, key[0])
+		default:
+			return nil, fmt.Errorf("invalid detach key %q in %q", key, spec)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command_test
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/runfinch/common-tests/command"
+)
+
+var _ = ginkgo.Describe("Terminal", func() {
+	ginkgo.BeforeEach(func() {
+		if runtime.GOOS == "windows" {
+			ginkgo.Skip("pseudo-terminals are not supported on Windows")
+		}
+	})
+
+	ginkgo.It("should send the keystrokes to the command and read its output", func() {
+		o, log := newFakeOption("rules:\n  - args: ^run -it\n    stdout: \"ready\\n\"\n    echoStdin: true\n")
+		terminal := command.StartInTerminal(o, "run", "-it", "alpine")
+		gomega.Eventually(terminal).Should(gbytes.Say("ready"))
+		terminal.SendLine("hello")
+		gomega.Eventually(terminal).Should(gbytes.Say("hello"))
+		// ctrl-d ends the input at the beginning of a line.
+		terminal.Send("\x04")
+		gomega.Eventually(terminal).WithTimeout(5 * time.Second).Should(gexec.Exit(0))
+		gomega.Expect(invocations(log)).Should(gomega.Equal([][]string{{"run", "-it", "alpine"}}))
+	})
+
+	ginkgo.It("should report the exit code of the command", func() {
+		o, _ := newFakeOption("default: {exitCode: 3}\n")
+		terminal := command.StartInTerminal(o, "attach", "ctr").Wait(5 * time.Second)
+		gomega.Expect(terminal.ExitCode()).Should(gomega.Equal(3))
+	})
+
+	ginkgo.It("should kill the command when the context is done", func() {
+		o, _ := newFakeOption("default: {delay: 1m}\n")
+		ctx, cancel := context.WithCancel(context.Background())
+		terminal := command.New(o, "attach", "ctr").WithContext(ctx).StartInTerminal()
+		gomega.Consistently(terminal.Exited).WithTimeout(200 * time.Millisecond).ShouldNot(gomega.BeClosed())
+		cancel()
+		gomega.Eventually(terminal.Exited).WithTimeout(5 * time.Second).Should(gomega.BeClosed())
+	})
+
+	ginkgo.DescribeTable("DetachKeys",
+		func(spec string, want []byte, wantErr bool) {
+			keys, err := command.DetachKeys(spec)
+			if wantErr {
+				gomega.Expect(err).Should(gomega.HaveOccurred())
+				return
+			}
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Expect(keys).Should(gomega.Equal(want))
+		},
+		ginkgo.Entry("default", command.DefaultDetachKeys, []byte{0x10, 0x11}, false),
+		ginkgo.Entry("characters", "a,ctrl-a,ctrl-[,ctrl-@", []byte{'a', 0x01, 0x1b, 0x00}, false),
+		ginkgo.Entry("invalid control key", "ctrl-1", nil, true),
+		ginkgo.Entry("invalid key", "ab", nil, true),
+		ginkgo.Entry("empty", "", nil, true),
+	)
+})
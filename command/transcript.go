@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/runfinch/common-tests/transcript"
+)
+
+// recorder records a command to the transcript of its option (see option.WithTranscript).
+type recorder struct {
+	path    string
+	subject []string
+	start   time.Time
+	stdin   hash.Hash
+	done    chan struct{}
+}
+
+// newRecorder returns nil if the option of c does not record the commands.
+// Otherwise, it hashes the stdin of cmd while it's read.
+func (c *Command) newRecorder(cmd *exec.Cmd) *recorder {
+	path := c.opt.Transcript()
+	if path == "" {
+		return nil
+	}
+	// The subject is taken from the option rather than cmd because a transport (e.g., option.SSHTransport)
+	// may wrap it in a command whose arguments no longer end with the ones of the subject.
+	r := &recorder{path: path, subject: c.opt.Subject(), start: time.Now(), done: make(chan struct{})}
+	if cmd.Stdin != nil {
+		r.stdin = sha256.New()
+		cmd.Stdin = io.TeeReader(cmd.Stdin, r.stdin)
+	}
+	return r
+}
+
+// recordOnExit appends the record of the session to the transcript after it exits.
+func (r *recorder) recordOnExit(cmd *exec.Cmd, args []string, session *gexec.Session) {
+	go func() {
+		defer close(r.done)
+		<-session.Exited
+		record := transcript.Record{
+			Subject:  r.subject,
+			Args:     args,
+			Env:      envDelta(cmd.Env),
+			Stdout:   string(session.Out.Contents()),
+			Stderr:   string(session.Err.Contents()),
+			ExitCode: session.ExitCode(),
+			Duration: time.Since(r.start),
+		}
+		if r.stdin != nil {
+			record.StdinSHA256 = hex.EncodeToString(r.stdin.Sum(nil))
+		}
+		if err := transcript.Append(r.path, record); err != nil {
+			ginkgo.GinkgoWriter.Printf("Failed to record %q: %v\n", args, err)
+		}
+	}()
+}
+
+// envDelta returns the environment variables in env that are not inherited from the test process.
+func envDelta(env []string) []string {
+	inherited := os.Environ()
+	var delta []string
+	for _, e := range env {
+		if !slices.Contains(inherited, e) {
+			delta = append(delta, e)
+		}
+	}
+	return delta
+}
+
+// waitForRecord waits until session, which is the last session started by c, is recorded
+// if it has exited and the option of c records the commands.
+func (c *Command) waitForRecord(session *gexec.Session) {
+	if c.recorder == nil {
+		return
+	}
+	select {
+	case <-session.Exited:
+		<-c.recorder.done
+	default:
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/transcript"
+)
+
+var _ = ginkgo.Describe("Transcript", func() {
+	ginkgo.It("should record every command and replay them", func() {
+		path := filepath.Join(ginkgo.GinkgoT().TempDir(), "transcript.jsonl")
+		o, _ := newFakeOption(`
+rules:
+  - args: ^ps -q$
+    stdout: "id1\n"
+  - args: ^login
+    echoStdin: true
+  - args: ^pull
+    stderr: not found
+    exitCode: 1
+`, option.WithTranscript(path), option.Env([]string{"FOO=bar"}))
+		command.Run(o, "ps", "-q")
+		command.New(o, "login", "--password-stdin").WithStdin(gbytes.BufferWithBytes([]byte("secret"))).Run()
+		command.RunWithoutSuccessfulExit(o, "pull", "alpine")
+
+		records, err := transcript.Read(path)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(records).Should(gomega.HaveLen(3))
+		gomega.Expect(records[0].Subject).Should(gomega.Equal(o.Subject()))
+		gomega.Expect(records[0].Args).Should(gomega.Equal([]string{"ps", "-q"}))
+		gomega.Expect(records[0].Env).Should(gomega.Equal([]string{"FOO=bar"}))
+		gomega.Expect(records[0].Stdout).Should(gomega.Equal("id1\n"))
+		gomega.Expect(records[0].StdinSHA256).Should(gomega.BeEmpty())
+		gomega.Expect(records[1].StdinSHA256).Should(gomega.Equal("2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"))
+		gomega.Expect(records[2].Stderr).Should(gomega.Equal("not found"))
+		gomega.Expect(records[2].ExitCode).Should(gomega.Equal(1))
+		gomega.Expect(records[2].Duration).Should(gomega.BeNumerically(">", 0))
+
+		// Replay the transcript without any rule.
+		scenario := filepath.Join(ginkgo.GinkgoT().TempDir(), "replay.yaml")
+		gomega.Expect(os.WriteFile(scenario, []byte("replay: "+path+"\n"), 0o600)).Should(gomega.Succeed())
+		replay, err := option.New([]string{fakeSubject, scenario})
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(command.StdoutStr(replay, "ps", "-q")).Should(gomega.Equal("id1"))
+		gomega.Expect(command.RunWithoutSuccessfulExit(replay, "pull", "alpine").Err.Contents()).Should(gomega.Equal([]byte("not found")))
+	})
+
+	ginkgo.It("should record the subject and the args of a command run through the SSH transport", func() {
+		if runtime.GOOS == "windows" {
+			ginkgo.Skip("the fake ssh binary is a shell script")
+		}
+		// The fake ssh binary runs the remote command locally, ignoring the options and the destination before "--".
+		ssh := filepath.Join(ginkgo.GinkgoT().TempDir(), "ssh")
+		script := "#!/bin/sh\nwhile [ \"$1\" != -- ]; do shift; done\nexec sh -c \"$2\"\n"
+		gomega.Expect(os.WriteFile(ssh, []byte(script), 0o700)).Should(gomega.Succeed())
+
+		path := filepath.Join(ginkgo.GinkgoT().TempDir(), "transcript.jsonl")
+		o, _ := newFakeOption(`
+rules:
+  - args: ^run
+    stdout: "id1\n"
+`, option.WithTranscript(path), option.WithTransport(option.SSHTransport{Destination: "host", Args: []string{"-p", "2222"}, Binary: ssh}))
+		args := []string{"run", "-d", "-p", "8080:80", "--name", "x", "alpine", "sleep", "infinity"}
+		gomega.Expect(command.StdoutStr(o, args...)).Should(gomega.Equal("id1"))
+
+		records, err := transcript.Read(path)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(records).Should(gomega.HaveLen(1))
+		gomega.Expect(records[0].Subject).Should(gomega.Equal(o.Subject()))
+		gomega.Expect(records[0].Args).Should(gomega.Equal(args))
+		gomega.Expect(records[0].Stdout).Should(gomega.Equal("id1\n"))
+	})
+})
@@ -0,0 +1,15 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Command fake-subject is a scriptable fake test subject. See the fake package for more details.
+package main
+
+import (
+	"os"
+
+	"github.com/runfinch/common-tests/fake"
+)
+
+func main() {
+	os.Exit(fake.Main(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
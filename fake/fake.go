@@ -0,0 +1,220 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fake implements a scriptable fake test subject, which is used to test the helpers in this repository
+// (e.g., the command package) without a real container CLI.
+//
+// The fake subject is the binary built from ./cmd/fake-subject. Its first argument is the path to a scenario file,
+// and the rest of the arguments are handled as if they were passed to a real test subject, so it can be used as
+//
+//	option.New([]string{"/path/to/fake-subject", "/path/to/scenario.yaml"})
+//
+// See Scenario for the format of the scenario file.
+//
+// The fake subject can also replay a transcript recorded with option.WithTranscript (see Scenario.Replay),
+// which is useful for debugging a failure offline and for regression-testing the helpers against real outputs.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/runfinch/common-tests/transcript"
+)
+
+// Scenario describes how the fake subject responds to its invocations. For example:
+//
+//	log: /tmp/invocations.jsonl
+//	rules:
+//	  - args: ^ps --all --quiet --no-trunc$
+//	    stdout: |
+//	      id1
+//	      id2
+//	  - args: ^rm --force
+//	    delay: 100ms
+//	  - args: ^pull ne-repo
+//	    stderr: not found
+//	    exitCode: 1
+type Scenario struct {
+	// Log is the file that every invocation is appended to as a JSON array of its arguments, one invocation per line.
+	// It's optional and is useful for asserting which commands were run.
+	Log string `yaml:"log"`
+	// Rules are checked in order, and the first one matching the arguments determines the response.
+	Rules []Rule `yaml:"rules"`
+	// Default is the response when no rule matches. If not specified, the fake subject exits with 1 and complains on stderr.
+	Default *Rule `yaml:"default"`
+	// Replay is the path to a transcript (see the transcript package) whose records are checked before Rules.
+	// The nth invocation with some arguments is responded with the nth record with the same arguments (or the last one),
+	// where the arguments that change from run to run (e.g., ports and temporary directories) are ignored (see transcript.Match),
+	// so Log must be set for the repeated invocations (e.g., retries) to get different responses.
+	Replay string `yaml:"replay"`
+
+	records []transcript.Record
+}
+
+// Rule maps the arguments of an invocation to a response.
+type Rule struct {
+	// Args is a regular expression that is matched against the arguments joined by spaces.
+	Args string `yaml:"args"`
+	// Stdout is written to stdout.
+	Stdout string `yaml:"stdout"`
+	// Stderr is written to stderr.
+	Stderr string `yaml:"stderr"`
+	// ExitCode is the exit code of the invocation.
+	ExitCode int `yaml:"exitCode"`
+	// Delay is how long to wait before responding (e.g., 100ms).
+	Delay time.Duration `yaml:"delay"`
+	// EchoStdin copies stdin to stdout after Stdout is written, like `cat`.
+	EchoStdin bool `yaml:"echoStdin"`
+
+	args *regexp.Regexp
+}
+
+// LoadScenario reads a Scenario from a YAML or JSON file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	var s Scenario
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if s.Replay != "" {
+		if s.records, err = transcript.Read(s.Replay); err != nil {
+			return nil, err
+		}
+	}
+	for i := range s.Rules {
+		if s.Rules[i].args, err = regexp.Compile(s.Rules[i].Args); err != nil {
+			return nil, fmt.Errorf("failed to compile the args of rule %d: %w", i, err)
+		}
+	}
+	return &s, nil
+}
+
+// Match returns the rule that responds to args.
+func (s *Scenario) Match(args []string) Rule {
+	joined := strings.Join(args, " ")
+	for _, r := range s.Rules {
+		if r.args.MatchString(joined) {
+			return r
+		}
+	}
+	if s.Default != nil {
+		return *s.Default
+	}
+	return Rule{
+		Stderr:   fmt.Sprintf("fake subject: no rule matches %q\n", joined),
+		ExitCode: 1,
+	}
+}
+
+// Main is the entry point of the fake subject. args[0] is the path to the scenario file,
+// and the rest are the arguments passed to the subject. It returns the exit code.
+func Main(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: fake-subject <scenario> [args...]")
+		return 2
+	}
+	s, err := LoadScenario(args[0])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	args = args[1:]
+	r, err := s.respond(args)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if err := s.record(args); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	time.Sleep(r.Delay)
+	fmt.Fprint(stdout, r.Stdout)
+	if r.EchoStdin {
+		if _, err := io.Copy(stdout, stdin); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	fmt.Fprint(stderr, r.Stderr)
+	return r.ExitCode
+}
+
+// respond returns the recorded response to args if there is one, or the rule matching args otherwise.
+func (s *Scenario) respond(args []string) (Rule, error) {
+	if len(s.records) == 0 {
+		return s.Match(args), nil
+	}
+	n := 0
+	if s.Log != "" {
+		invocations, err := Invocations(s.Log)
+		if err != nil {
+			return Rule{}, err
+		}
+		for _, inv := range invocations {
+			if transcript.Match(inv, args) {
+				n++
+			}
+		}
+	}
+	if r, ok := transcript.Find(s.records, args, n); ok {
+		return Rule{Stdout: r.Stdout, Stderr: r.Stderr, ExitCode: r.ExitCode}, nil
+	}
+	return s.Match(args), nil
+}
+
+func (s *Scenario) record(args []string) error {
+	if s.Log == "" {
+		return nil
+	}
+	line, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Clean(s.Log), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the invocation log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to record the invocation: %w", err)
+	}
+	return f.Close()
+}
+
+// Invocations reads the invocations recorded in log, which is the Log of a Scenario.
+// It returns an empty slice if nothing has been recorded yet.
+func Invocations(log string) ([][]string, error) {
+	data, err := os.ReadFile(filepath.Clean(log))
+	if os.IsNotExist(err) {
+		return [][]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	invocations := [][]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var args []string
+		if err := json.Unmarshal([]byte(line), &args); err != nil {
+			return nil, fmt.Errorf("failed to parse invocation %q: %w", line, err)
+		}
+		invocations = append(invocations, args)
+	}
+	return invocations, nil
+}
@@ -0,0 +1,167 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fake
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/runfinch/common-tests/transcript"
+)
+
+const scenario = `
+rules:
+  - args: ^ps -q$
+    stdout: |
+      id1
+      id2
+  - args: ^pull ne-repo
+    stderr: not found
+    exitCode: 1
+  - args: ^exec -i ctr cat$
+    echoStdin: true
+`
+
+func writeScenario(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMainResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		args         []string
+		stdin        string
+		wantStdout   string
+		wantStderr   string
+		wantExitCode int
+	}{
+		{
+			name:       "MatchesStdout",
+			args:       []string{"ps", "-q"},
+			wantStdout: "id1\nid2\n",
+		},
+		{
+			name:         "MatchesFailure",
+			args:         []string{"pull", "ne-repo:ne-tag"},
+			wantStderr:   "not found",
+			wantExitCode: 1,
+		},
+		{
+			name:       "EchoesStdin",
+			args:       []string{"exec", "-i", "ctr", "cat"},
+			stdin:      "hello",
+			wantStdout: "hello",
+		},
+		{
+			name:         "FallsBackWhenNothingMatches",
+			args:         []string{"images"},
+			wantStderr:   "fake subject: no rule matches \"images\"\n",
+			wantExitCode: 1,
+		},
+	}
+
+	path := writeScenario(t, scenario)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			exitCode := Main(append([]string{path}, test.args...), strings.NewReader(test.stdin), &stdout, &stderr)
+			if exitCode != test.wantExitCode {
+				t.Fatalf("expected exit code %d, got %d", test.wantExitCode, exitCode)
+			}
+			if stdout.String() != test.wantStdout {
+				t.Fatalf("expected stdout %q, got %q", test.wantStdout, stdout.String())
+			}
+			if stderr.String() != test.wantStderr {
+				t.Fatalf("expected stderr %q, got %q", test.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestInvocations(t *testing.T) {
+	t.Parallel()
+
+	log := filepath.Join(t.TempDir(), "invocations.jsonl")
+	path := writeScenario(t, "log: "+log+"\ndefault: {}\n")
+
+	got, err := Invocations(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no invocations, got %v", got)
+	}
+
+	var stdout, stderr bytes.Buffer
+	Main([]string{path, "run", "--name", "a b"}, strings.NewReader(""), &stdout, &stderr)
+	Main([]string{path, "rm", "-f"}, strings.NewReader(""), &stdout, &stderr)
+
+	got, err = Invocations(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"run", "--name", "a b"}, {"rm", "-f"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected invocations %v, got %v", want, got)
+	}
+}
+
+func TestLoadScenarioRejectsInvalidRegexp(t *testing.T) {
+	t.Parallel()
+
+	if _, err := LoadScenario(writeScenario(t, "rules:\n  - args: \"(\"\n")); err == nil {
+		t.Fatal("expected LoadScenario to fail")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	records := filepath.Join(dir, "transcript.jsonl")
+	for _, r := range []transcript.Record{
+		{Args: []string{"pull", "alpine"}, Stderr: "timeout", ExitCode: 1},
+		{Args: []string{"ps", "-q"}, Stdout: "id\n"},
+		{Args: []string{"pull", "alpine"}, Stdout: "pulled"},
+	} {
+		if err := transcript.Append(records, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log := filepath.Join(dir, "invocations.jsonl")
+	path := writeScenario(t, "log: "+log+"\nreplay: "+records+"\nrules:\n  - args: ^images$\n    stdout: from rule\n")
+
+	tests := []struct {
+		args     []string
+		stdout   string
+		exitCode int
+	}{
+		{args: []string{"pull", "alpine"}, exitCode: 1},
+		{args: []string{"pull", "alpine"}, stdout: "pulled"},
+		{args: []string{"pull", "alpine"}, stdout: "pulled"},
+		{args: []string{"ps", "-q"}, stdout: "id\n"},
+		{args: []string{"images"}, stdout: "from rule"},
+		{args: []string{"rm"}, exitCode: 1},
+	}
+	for i, test := range tests {
+		var stdout, stderr bytes.Buffer
+		exitCode := Main(append([]string{path}, test.args...), strings.NewReader(""), &stdout, &stderr)
+		if exitCode != test.exitCode || stdout.String() != test.stdout {
+			t.Fatalf("invocation %d %v: got (%d, %q), want (%d, %q)", i, test.args, exitCode, stdout.String(), test.exitCode, test.stdout)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 
 	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/option"
 )
 
 // CreateBuildContext creates a directory which contains a Dockerfile with the specified content and returns the path to the directory.
@@ -85,3 +87,10 @@ func DeleteDirectory(directoryPath string) {
 	err := os.RemoveAll(directoryPath)
 	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 }
+
+// Stage makes the files or directories specified by their absolute paths available to the subject of o at the same paths.
+// It's only needed for the paths that are not passed to the subject as arguments, which are staged by command.Run.
+// For more details, see option.Option.Stage.
+func Stage(o *option.Option, paths ...string) {
+	gomega.Expect(o.Stage(paths...)).Should(gomega.Succeed())
+}
@@ -9,28 +9,27 @@ import (
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
 )
 
 // HTTPGetAndAssert sends an HTTP GET request to the specified URL, asserts the response status code against want, and closes the response body.
+// The request is attempted up to maxRetry times, waiting for retryInterval between the attempts (see command.Retry).
 func HTTPGetAndAssert(url string, want int, maxRetry int, retryInterval time.Duration) {
-	var (
-		err  error
-		resp *http.Response
-	)
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
 
-	for i := 0; i < maxRetry; i++ {
+	var resp *http.Response
+	err := command.Retry("GET "+url, maxRetry, retryInterval, func() error {
+		var err error
 		// #nosec G107 // it does not matter if url is not a constant for testing.
 		resp, err = client.Get(url)
-		if err != nil {
-			time.Sleep(retryInterval)
-			continue
-		}
-		defer func() { gomega.Expect(resp.Body.Close()).To(gomega.Succeed()) }()
-		gomega.Expect(resp.StatusCode).To(gomega.Equal(want))
-		return
+		return err
+	})
+	if err != nil {
+		ginkgo.Fail(err.Error())
 	}
-	ginkgo.Fail(err.Error())
+	defer func() { gomega.Expect(resp.Body.Close()).To(gomega.Succeed()) }()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(want))
 }
@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+// ContainerInspect is the output of "inspect" for a container.
+type ContainerInspect struct {
+	ID              string          `json:"Id"`
+	Name            string          `json:"Name"`
+	Image           string          `json:"Image"`
+	Created         string          `json:"Created"`
+	Path            string          `json:"Path"`
+	Args            []string        `json:"Args"`
+	RestartCount    int             `json:"RestartCount"`
+	State           ContainerState  `json:"State"`
+	Config          ContainerConfig `json:"Config"`
+	HostConfig      HostConfig      `json:"HostConfig"`
+	Mounts          []Mount         `json:"Mounts"`
+	NetworkSettings NetworkSettings `json:"NetworkSettings"`
+}
+
+// ContainerState is the state of a container.
+type ContainerState struct {
+	Status     string `json:"Status"`
+	Running    bool   `json:"Running"`
+	Paused     bool   `json:"Paused"`
+	Restarting bool   `json:"Restarting"`
+	Pid        int    `json:"Pid"`
+	ExitCode   int    `json:"ExitCode"`
+	Error      string `json:"Error"`
+	StartedAt  string `json:"StartedAt"`
+	FinishedAt string `json:"FinishedAt"`
+	// Health is only reported by nerdctl 2.x, and only for containers with a health check.
+	Health *Health `json:"Health"`
+}
+
+// Health is the health status of a container.
+type Health struct {
+	Status        string      `json:"Status"`
+	FailingStreak int         `json:"FailingStreak"`
+	Log           []HealthLog `json:"Log"`
+}
+
+// HealthLog is the result of a single health check probe.
+type HealthLog struct {
+	Start    string `json:"Start"`
+	End      string `json:"End"`
+	ExitCode int    `json:"ExitCode"`
+	Output   string `json:"Output"`
+}
+
+// ContainerConfig is the configuration of a container.
+type ContainerConfig struct {
+	Hostname   string            `json:"Hostname"`
+	User       string            `json:"User"`
+	WorkingDir string            `json:"WorkingDir"`
+	Env        []string          `json:"Env"`
+	Cmd        []string          `json:"Cmd"`
+	Entrypoint []string          `json:"Entrypoint"`
+	Labels     map[string]string `json:"Labels"`
+	// Healthcheck is only reported by nerdctl 2.x.
+	Healthcheck *Healthcheck `json:"Healthcheck"`
+}
+
+// Healthcheck is the health check configuration of a container.
+type Healthcheck struct {
+	Test          []string `json:"Test"`
+	Interval      Duration `json:"Interval"`
+	Timeout       Duration `json:"Timeout"`
+	StartPeriod   Duration `json:"StartPeriod"`
+	StartInterval Duration `json:"StartInterval"`
+	Retries       int      `json:"Retries"`
+}
+
+// HostConfig is the host configuration of a container. nerdctl 1.x reports fewer fields than nerdctl 2.x.
+type HostConfig struct {
+	NetworkMode   string        `json:"NetworkMode"`
+	CgroupnsMode  string        `json:"CgroupnsMode"`
+	ShmSize       int64         `json:"ShmSize"`
+	Memory        int64         `json:"Memory"`
+	NanoCPUs      int64         `json:"NanoCpus"`
+	CPUShares     int64         `json:"CpuShares"`
+	RestartPolicy RestartPolicy `json:"RestartPolicy"`
+}
+
+// RestartPolicy is the restart policy of a container.
+type RestartPolicy struct {
+	Name              string `json:"Name"`
+	MaximumRetryCount int    `json:"MaximumRetryCount"`
+}
+
+// Mount is a mount of a container.
+type Mount struct {
+	Type        string `json:"Type"`
+	Name        string `json:"Name"`
+	Source      string `json:"Source"`
+	Destination string `json:"Destination"`
+	Mode        string `json:"Mode"`
+	RW          bool   `json:"RW"`
+}
+
+// NetworkSettings is the network settings of a container.
+type NetworkSettings struct {
+	Ports    map[string][]PortBinding    `json:"Ports"`
+	Networks map[string]EndpointSettings `json:"Networks"`
+}
+
+// PortBinding is a binding of a container port to a host port.
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// EndpointSettings is the settings of a container in a network.
+type EndpointSettings struct {
+	IPAddress   string `json:"IPAddress"`
+	IPPrefixLen int    `json:"IPPrefixLen"`
+	Gateway     string `json:"Gateway"`
+	MacAddress  string `json:"MacAddress"`
+}
+
+// IPAddresses returns the IP addresses of the container in all of its networks.
+func (s NetworkSettings) IPAddresses() []string {
+	var ips []string
+	for _, n := range s.Networks {
+		if n.IPAddress != "" {
+			ips = append(ips, n.IPAddress)
+		}
+	}
+	return ips
+}
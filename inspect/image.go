@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+// ImageInspect is the output of "image inspect".
+type ImageInspect struct {
+	ID           string      `json:"Id"`
+	RepoTags     []string    `json:"RepoTags"`
+	RepoDigests  []string    `json:"RepoDigests"`
+	Created      string      `json:"Created"`
+	Comment      string      `json:"Comment"`
+	Author       string      `json:"Author"`
+	Architecture string      `json:"Architecture"`
+	Variant      string      `json:"Variant"`
+	Os           string      `json:"Os"`
+	Size         int64       `json:"Size"`
+	Config       ImageConfig `json:"Config"`
+	RootFS       RootFS      `json:"RootFS"`
+}
+
+// ImageConfig is the configuration of an image.
+type ImageConfig struct {
+	User         string              `json:"User"`
+	WorkingDir   string              `json:"WorkingDir"`
+	Env          []string            `json:"Env"`
+	Cmd          []string            `json:"Cmd"`
+	Entrypoint   []string            `json:"Entrypoint"`
+	Labels       map[string]string   `json:"Labels"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+	Healthcheck  *Healthcheck        `json:"Healthcheck"`
+}
+
+// RootFS is the root file system of an image.
+type RootFS struct {
+	Type   string   `json:"Type"`
+	Layers []string `json:"Layers"`
+}
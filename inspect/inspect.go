@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package inspect decodes the JSON output of the inspect commands into typed structs.
+//
+// The structs only model the fields that the tests use. Every field is optional so that the output of both
+// nerdctl 1.x and 2.x (see option.IsNerdctlV1 and option.IsNerdctlV2) can be decoded by the same struct:
+// fields that only exist in one of the versions are left as zero values (or nil pointers) in the other one.
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+)
+
+// Container runs "inspect" against the container specified by name and decodes the output.
+func Container(o *option.Option, name string) ContainerInspect {
+	var c ContainerInspect
+	decodeInspect(o, &c, "inspect", "--type", "container", name)
+	c.Name = strings.TrimPrefix(c.Name, "/")
+	return c
+}
+
+// Image runs "image inspect" against the image specified by name and decodes the output.
+func Image(o *option.Option, name string) ImageInspect {
+	var i ImageInspect
+	decodeInspect(o, &i, "image", "inspect", name)
+	return i
+}
+
+// Volume runs "volume inspect" against the volume specified by name and decodes the output.
+func Volume(o *option.Option, name string) VolumeInspect {
+	var v VolumeInspect
+	decodeInspect(o, &v, "volume", "inspect", name)
+	return v
+}
+
+// Network runs "network inspect" against the network specified by name and decodes the output.
+func Network(o *option.Option, name string) NetworkInspect {
+	var n NetworkInspect
+	decodeInspect(o, &n, "network", "inspect", name)
+	return n
+}
+
+func decodeInspect(o *option.Option, v any, args ...string) {
+	err := decode(command.Stdout(o, args...), v)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred(), "failed to decode the output of %q", strings.Join(args, " "))
+}
+
+// decode decodes the output of an inspect command for a single object into v.
+// The output is a JSON array with one element, but a bare object is accepted too.
+func decode(data []byte, v any) error {
+	var objs []json.RawMessage
+	if err := json.Unmarshal(data, &objs); err != nil {
+		return json.Unmarshal(data, v)
+	}
+	if len(objs) != 1 {
+		return fmt.Errorf("expected 1 object in the inspect output, got %d", len(objs))
+	}
+	return json.Unmarshal(objs[0], v)
+}
+
+// Duration is a time.Duration that can be decoded from either a number of nanoseconds, which is what
+// Docker-compatible output contains, or a duration string such as "30s".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" {
+			*d = 0
+			return nil
+		}
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("duration must be a string or a number of nanoseconds: %w", err)
+	}
+	*d = Duration(n)
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeContainer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		data   string
+		assert func(*testing.T, ContainerInspect)
+	}{
+		{
+			name: "NerdctlV1WithoutHealth",
+			data: `[{"Id":"abc","Name":"ctr","State":{"Status":"running","Running":true,"Pid":42},
+				"Config":{"Hostname":"abc","Labels":null},"Mounts":null,
+				"NetworkSettings":{"Ports":null,"Networks":{"unknown-eth0":{"IPAddress":"10.4.0.2"}}}}]`,
+			assert: func(t *testing.T, c ContainerInspect) {
+				if c.ID != "abc" || c.State.Status != "running" || c.State.Pid != 42 {
+					t.Fatalf("unexpected container: %+v", c)
+				}
+				if c.State.Health != nil || c.Config.Healthcheck != nil {
+					t.Fatal("expected health fields to be nil")
+				}
+				if got := c.NetworkSettings.IPAddresses(); !reflect.DeepEqual(got, []string{"10.4.0.2"}) {
+					t.Fatalf("unexpected IP addresses: %v", got)
+				}
+			},
+		},
+		{
+			name: "NerdctlV2WithHealth",
+			data: `[{"Id":"abc","State":{"Status":"running","Health":{"Status":"healthy","FailingStreak":0,
+				"Log":[{"ExitCode":0,"Output":"ok"}]}},
+				"Config":{"Healthcheck":{"Test":["CMD","true"],"Interval":30000000000,"Timeout":"5s","Retries":3}},
+				"HostConfig":{"Memory":1024,"RestartPolicy":{"Name":"always"}}}]`,
+			assert: func(t *testing.T, c ContainerInspect) {
+				if c.State.Health == nil || c.State.Health.Status != "healthy" || len(c.State.Health.Log) != 1 {
+					t.Fatalf("unexpected health: %+v", c.State.Health)
+				}
+				hc := c.Config.Healthcheck
+				if hc == nil || time.Duration(hc.Interval) != 30*time.Second || time.Duration(hc.Timeout) != 5*time.Second {
+					t.Fatalf("unexpected health check: %+v", hc)
+				}
+				if c.HostConfig.Memory != 1024 || c.HostConfig.RestartPolicy.Name != "always" {
+					t.Fatalf("unexpected host config: %+v", c.HostConfig)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var c ContainerInspect
+			if err := decode([]byte(test.data), &c); err != nil {
+				t.Fatal(err)
+			}
+			test.assert(t, c)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "Image",
+			data: `[{"Id":"sha256:abc","RepoTags":["alpine:latest"],"RepoDigests":null,"Os":"linux",
+				"Config":{"Cmd":["/bin/sh"],"Labels":null}}]`,
+			want: &ImageInspect{ID: "sha256:abc", RepoTags: []string{"alpine:latest"}, Os: "linux", Config: ImageConfig{Cmd: []string{"/bin/sh"}}},
+		},
+		{
+			name: "Volume",
+			data: `[{"Name":"vol","Mountpoint":"/var/lib/vol","Labels":{"k":"v"}}]`,
+			want: &VolumeInspect{Name: "vol", Mountpoint: "/var/lib/vol", Labels: map[string]string{"k": "v"}},
+		},
+		{
+			name: "NetworkWithoutContainers",
+			data: `[{"Name":"net","Id":"123","IPAM":{"Config":[{"Subnet":"10.5.0.0/16","Gateway":"10.5.0.1"}]}}]`,
+			want: &NetworkInspect{ID: "123", Name: "net", IPAM: IPAM{Config: []IPAMConfig{{Subnet: "10.5.0.0/16", Gateway: "10.5.0.1"}}}},
+		},
+		{
+			name: "BareObject",
+			data: `{"Name":"vol"}`,
+			want: &VolumeInspect{Name: "vol"},
+		},
+		{
+			name:    "MultipleObjects",
+			data:    `[{"Name":"vol1"},{"Name":"vol2"}]`,
+			want:    &VolumeInspect{},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := reflect.New(reflect.TypeOf(test.want).Elem()).Interface()
+			err := decode([]byte(test.data), got)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDurationUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{data: `1000000000`, want: time.Second},
+		{data: `"1m30s"`, want: 90 * time.Second},
+		{data: `""`, want: 0},
+		{data: `null`, want: 0},
+		{data: `"soon"`, wantErr: true},
+		{data: `true`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.data, func(t *testing.T) {
+			t.Parallel()
+
+			var d Duration
+			err := d.UnmarshalJSON([]byte(test.data))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if time.Duration(d) != test.want {
+				t.Fatalf("got %v, want %v", time.Duration(d), test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inspect
+
+// NetworkInspect is the output of "network inspect".
+type NetworkInspect struct {
+	ID     string            `json:"Id"`
+	Name   string            `json:"Name"`
+	Driver string            `json:"Driver"`
+	IPAM   IPAM              `json:"IPAM"`
+	Labels map[string]string `json:"Labels"`
+	// Containers is only reported by nerdctl 2.x.
+	Containers map[string]NetworkContainer `json:"Containers"`
+}
+
+// IPAM is the IP address management configuration of a network.
+type IPAM struct {
+	Driver string       `json:"Driver"`
+	Config []IPAMConfig `json:"Config"`
+}
+
+// IPAMConfig is a single IP address pool of a network.
+type IPAMConfig struct {
+	Subnet  string `json:"Subnet"`
+	IPRange string `json:"IPRange"`
+	Gateway string `json:"Gateway"`
+}
+
+// NetworkContainer is a container attached to a network.
+type NetworkContainer struct {
+	Name        string `json:"Name"`
+	IPv4Address string `json:"IPv4Address"`
+	IPv6Address string `json:"IPv6Address"`
+	MacAddress  string `json:"MacAddress"`
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ImageCatalog maps the names of the images used by the tests to the references that they are pulled from.
+//
+// The names understood by the tests are "defaultImage", "olderAlpineImage", "amazonLinux2Image", "nginxImage",
+// and "registryImage". An image that is not in the catalog keeps its default reference.
+//
+// A reference can be pinned by digest (e.g., "public.ecr.aws/docker/library/alpine:3.13@sha256:<hex>"),
+// in which case the digest of the image is verified before the image is used.
+type ImageCatalog map[string]string
+
+// LoadImageCatalog reads an ImageCatalog from a YAML or JSON file that maps image names to references.
+// For example:
+//
+//	defaultImage: registry.example.com/mirror/alpine:latest
+//	nginxImage: registry.example.com/mirror/nginx@sha256:<hex>
+func LoadImageCatalog(path string) (ImageCatalog, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image catalog: %w", err)
+	}
+	// YAML is a superset of JSON, so a JSON file can be parsed in the same way.
+	var c ImageCatalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse image catalog %s: %w", path, err)
+	}
+	for name, ref := range c {
+		if strings.TrimSpace(ref) == "" {
+			return nil, fmt.Errorf("image %s has an empty reference in image catalog %s", name, path)
+		}
+	}
+	return c, nil
+}
+
+// Digest returns the digest that the reference of the image named name is pinned by.
+// It returns an empty string if the image is not in the catalog or its reference is not pinned by digest.
+func (c ImageCatalog) Digest(name string) string {
+	_, digest, _ := strings.Cut(c[name], "@")
+	return digest
+}
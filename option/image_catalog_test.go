@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadImageCatalog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    ImageCatalog
+		wantErr bool
+	}{
+		{
+			name:    "YAML",
+			content: "defaultImage: mirror.example.com/alpine:latest\nnginxImage: mirror.example.com/nginx@sha256:abc\n",
+			want: ImageCatalog{
+				"defaultImage": "mirror.example.com/alpine:latest",
+				"nginxImage":   "mirror.example.com/nginx@sha256:abc",
+			},
+		},
+		{
+			name:    "JSON",
+			content: `{"defaultImage": "mirror.example.com/alpine:latest"}`,
+			want: ImageCatalog{
+				"defaultImage": "mirror.example.com/alpine:latest",
+			},
+		},
+		{
+			name:    "EmptyReference",
+			content: `defaultImage: ""`,
+			wantErr: true,
+		},
+		{
+			name:    "Malformed",
+			content: `[defaultImage]`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "catalog")
+			if err := os.WriteFile(path, []byte(test.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadImageCatalog(path)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected LoadImageCatalog to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+			for name, ref := range test.want {
+				if got[name] != ref {
+					t.Fatalf("expected %s to be %q, got %q", name, ref, got[name])
+				}
+			}
+		})
+	}
+}
+
+func TestImageCatalogDigest(t *testing.T) {
+	t.Parallel()
+
+	c := ImageCatalog{
+		"defaultImage": "public.ecr.aws/docker/library/alpine:latest",
+		"nginxImage":   "public.ecr.aws/docker/library/nginx:latest@sha256:abc",
+	}
+	if got := c.Digest("defaultImage"); got != "" {
+		t.Fatalf("expected no digest for an unpinned image, got %q", got)
+	}
+	if got := c.Digest("nginxImage"); got != "sha256:abc" {
+		t.Fatalf("expected digest to be sha256:abc, got %q", got)
+	}
+	if got := c.Digest("olderAlpineImage"); got != "" {
+		t.Fatalf("expected no digest for a missing image, got %q", got)
+	}
+}
@@ -49,3 +49,99 @@ func WithNerdctlVersion(version string) Modifier {
 		o.features[nerdctlVersion] = version
 	})
 }
+
+// WithImageArchiveDir seeds the local registry from the image archives in dir instead of pulling the images from the internet.
+//
+// dir may contain tarballs produced by `save` and OCI image-layout directories.
+// Each archive must keep the original reference of the image (e.g., public.ecr.aws/docker/library/alpine:latest)
+// so that it can be matched against the images used by the tests.
+//
+// This is useful for running the tests in an air-gapped environment.
+func WithImageArchiveDir(dir string) Modifier {
+	return newFuncModifier(func(o *Option) {
+		o.features[imageArchiveDir] = dir
+	})
+}
+
+// WithImageCatalog overrides the images used by the tests with the ones in c.
+//
+// This is useful for using mirrored images or images pinned by digest. See ImageCatalog for more details.
+func WithImageCatalog(c ImageCatalog) Modifier {
+	return newFuncModifier(func(o *Option) {
+		o.features[imageCatalog] = c
+	})
+}
+
+// WithInProcessRegistry serves the registries needed by the tests (e.g., the local registry and the ones used by Login)
+// from the test binary instead of running them as containers.
+//
+// The test subject must be able to reach localhost of the host that runs the tests. See the registry package for more details.
+func WithInProcessRegistry() Modifier {
+	return newFuncModifier(func(o *Option) {
+		o.features[inProcessRegistry] = true
+	})
+}
+
+// WithLabelScopedCleanup adds the SuiteLabel label to the objects (e.g., containers and volumes) created during testing,
+// and makes the cleanup functions in the command package (e.g., command.RemoveAll) only remove the objects with that label.
+//
+// This is useful for running the tests on a machine that has other containers, images, volumes or networks
+// that must not be removed. Note that the images that are pulled, loaded or tagged during testing are not labelled,
+// so they are left behind.
+func WithLabelScopedCleanup() Modifier {
+	return newFuncModifier(func(o *Option) {
+		o.SetLabel(SuiteLabel, "true")
+		o.features[labelScopedCleanup] = true
+	})
+}
+
+// WithTranscript records every command run through the command package to the JSONL transcript at path,
+// including its arguments, stdout, stderr and exit code. See the transcript package for the format.
+//
+// This is useful for debugging a failure offline by replaying the transcript with the fake subject (see the fake package).
+func WithTranscript(path string) Modifier {
+	return newFuncModifier(func(o *Option) {
+		o.features[transcriptPath] = path
+	})
+}
+
+// WithTransport runs the subject through t instead of on the host that runs the tests (see LocalTransport).
+//
+// This is useful for running the tests against a subject on another host, e.g., over SSH (see SSHTransport).
+func WithTransport(t Transport) Modifier {
+	return newFuncModifier(func(o *Option) {
+		o.features[transport] = t
+	})
+}
+
+// WithPathMapper translates the host paths into the paths where the subject runs with m (see Option.SubjectPath).
+// By default, WSLPathMapper is used on Windows, and IdentityPathMapper is used on the other platforms.
+func WithPathMapper(m PathMapper) Modifier {
+	return newFuncModifier(func(o *Option) {
+		o.features[pathMapper] = m
+	})
+}
+
+// WithMountPrefix maps the paths under the host directory to the paths under target, which is where the directory is
+// mounted where the subject runs, e.g., in a VM. It can be used multiple times. See MountPrefixes for more details.
+func WithMountPrefix(host, target string) Modifier {
+	return newFuncModifier(func(o *Option) {
+		prefixes, _ := o.features[pathMapper].(MountPrefixes)
+		o.features[pathMapper] = append(prefixes, PathMapping{Host: host, Target: target})
+	})
+}
+
+// WithComponentVersion overrides the version of the component (e.g., ComponentCNI) reported by Option.GetComponentVersions.
+// It can be used multiple times.
+//
+// This is useful for the components whose versions cannot be detected from the subject, e.g., the CNI plugins.
+func WithComponentVersion(component, version string) Modifier {
+	return newFuncModifier(func(o *Option) {
+		versions, _ := o.features[componentVersions].(map[string]string)
+		if versions == nil {
+			versions = map[string]string{}
+		}
+		versions[component] = version
+		o.features[componentVersions] = versions
+	})
+}
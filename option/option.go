@@ -7,10 +7,9 @@ package option
 import (
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
-	"path/filepath"
-	"regexp"
+	"slices"
+	"sort"
 	"strings"
 )
 
@@ -19,12 +18,19 @@ type feature int
 const (
 	environmentVariablePassthrough feature = iota
 	nerdctlVersion                 feature = iota
+	imageArchiveDir                feature = iota
+	imageCatalog                   feature = iota
+	inProcessRegistry              feature = iota
+	labelScopedCleanup             feature = iota
+	transcriptPath                 feature = iota
+	transport                      feature = iota
+	pathMapper                     feature = iota
+	componentVersions              feature = iota
 )
 
-var (
-	nerdctlVersionRegex      = regexp.MustCompile(`nerdctl\s+version\s+(\S+)`)
-	finchNerdctlVersionRegex = regexp.MustCompile(`nerdctl:\s+Version:\s+(\S+)`)
-)
+// SuiteLabel is the key of the label that is added to the objects created during testing when
+// WithLabelScopedCleanup is used.
+const SuiteLabel = "com.github.runfinch.common-tests"
 
 // Option customizes how tests are run.
 //
@@ -33,9 +39,14 @@ var (
 // For example, to test login functionality,
 // we may create a struct named LoginOption that embeds Option and contains additional fields like Username and Password.
 type Option struct {
-	subject  []string
-	env      []string
-	features map[feature]any
+	subject   []string
+	env       []string
+	labels    map[string]string
+	protected map[string]struct{}
+	features  map[feature]any
+
+	cleanupSuspended bool
+	staged           []string
 }
 
 // New does some sanity checks on the arguments before initializing an Option.
@@ -66,12 +77,62 @@ func New(subject []string, modifiers ...Modifier) (*Option, error) {
 }
 
 // NewCmd creates a command using the stored option and the provided args.
+// The command runs the subject through the transport of the option (see WithTransport).
 func (o *Option) NewCmd(args ...string) *exec.Cmd {
 	cmdName := o.subject[0]
 	cmdArgs := append(o.subject[1:], args...) //nolint:gocritic // appendAssign does not apply to our case.
-	cmd := exec.Command(cmdName, cmdArgs...)  //nolint:gosec // G204 is not an issue because cmdName is fully controlled by the user.
-	cmd.Env = append(os.Environ(), o.env...)
-	return cmd
+	return o.transport().Command(cmdName, cmdArgs, o.env)
+}
+
+// NewTerminalCmd is like NewCmd, but the subject sees a terminal when the returned command is started in a pseudo-terminal.
+// It returns an error if the transport cannot forward a terminal to the subject (see TerminalTransport).
+func (o *Option) NewTerminalCmd(args ...string) (*exec.Cmd, error) {
+	t, ok := o.transport().(TerminalTransport)
+	if !ok {
+		return nil, fmt.Errorf("the transport %T does not forward a terminal to the subject", o.transport())
+	}
+	return t.TerminalCommand(o.subject[0], append(append([]string{}, o.subject[1:]...), args...), o.env)
+}
+
+// Stage makes the local files or directories specified by their absolute paths available to the subject at the same paths.
+// It's a no-op unless the subject runs on another host (see WithTransport).
+//
+// Each path is only staged once until Unstage is called, so the changes made to it after it's staged are not seen by the subject.
+func (o *Option) Stage(paths ...string) error {
+	for _, p := range paths {
+		if slices.Contains(o.staged, p) {
+			continue
+		}
+		if err := o.transport().Stage(p); err != nil {
+			return err
+		}
+		o.staged = append(o.staged, p)
+	}
+	return nil
+}
+
+// StagedPaths returns the paths staged by Stage since Unstage was last called.
+func (o *Option) StagedPaths() []string {
+	return slices.Clone(o.staged)
+}
+
+// Unstage removes the files and directories staged by Stage from where the subject runs, and forgets about them.
+func (o *Option) Unstage() error {
+	var errs []error
+	for _, p := range o.staged {
+		errs = append(errs, o.transport().Unstage(p))
+	}
+	o.staged = nil
+	return errors.Join(errs...)
+}
+
+func (o *Option) transport() Transport {
+	if value, exists := o.features[transport]; exists {
+		if t, ok := value.(Transport); ok {
+			return t
+		}
+	}
+	return LocalTransport{}
 }
 
 // UpdateEnv updates the environment variable for the key name of the input.
@@ -91,6 +152,74 @@ func (o *Option) DeleteEnv(envKey string) {
 	}
 }
 
+// SetLabel sets a label that is added to the objects (e.g., containers and volumes) created during testing.
+// See command.Run for the commands that the labels are added to.
+func (o *Option) SetLabel(key, value string) {
+	if o.labels == nil {
+		o.labels = map[string]string{}
+	}
+	o.labels[key] = value
+}
+
+// DeleteLabel deletes the label for the key name of the input.
+func (o *Option) DeleteLabel(key string) {
+	delete(o.labels, key)
+}
+
+// Labels returns the labels set by SetLabel in the format of key=value, sorted by key.
+func (o *Option) Labels() []string {
+	keys := make([]string, 0, len(o.labels))
+	for k := range o.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	labels := make([]string, 0, len(keys))
+	for _, k := range keys {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, o.labels[k]))
+	}
+	return labels
+}
+
+// Protect prevents the objects (e.g., containers and images) specified by their IDs or names
+// from being removed by the cleanup functions in the command package (e.g., command.RemoveAll).
+func (o *Option) Protect(objs ...string) {
+	if o.protected == nil {
+		o.protected = map[string]struct{}{}
+	}
+	for _, obj := range objs {
+		o.protected[obj] = struct{}{}
+	}
+}
+
+// Unprotect reverts Protect for the objects specified by their IDs or names.
+func (o *Option) Unprotect(objs ...string) {
+	for _, obj := range objs {
+		delete(o.protected, obj)
+	}
+}
+
+// IsProtected returns true if the object specified by its ID or name is protected by Protect.
+func (o *Option) IsProtected(obj string) bool {
+	_, ok := o.protected[obj]
+	return ok
+}
+
+// SuspendCleanup makes the cleanup functions in the command package (e.g., command.RemoveAll) do nothing
+// until ResumeCleanup is called. It is used to find the objects that a spec does not remove by itself.
+func (o *Option) SuspendCleanup() {
+	o.cleanupSuspended = true
+}
+
+// ResumeCleanup reverts SuspendCleanup.
+func (o *Option) ResumeCleanup() {
+	o.cleanupSuspended = false
+}
+
+// IsCleanupSuspended returns true if the cleanup is suspended by SuspendCleanup.
+func (o *Option) IsCleanupSuspended() bool {
+	return o.cleanupSuspended
+}
+
 // containsEnv determines whether an environment variable exists.
 func containsEnv(envs []string, targetEnvKey string) (int, bool) {
 	for i, env := range envs {
@@ -135,46 +264,76 @@ func (o *Option) isNerdctlVersion(cmp func(string) bool) bool {
 	return cmp(version)
 }
 
-// Subject returns the subject stored in the option.
-func (o *Option) Subject() []string {
-	return o.subject
+// ImageArchiveDir returns the directory that the local registry is seeded from.
+// An empty string means that the images are pulled from their remote registries instead.
+func (o *Option) ImageArchiveDir() string {
+	if value, exists := o.features[imageArchiveDir]; exists {
+		if dir, ok := value.(string); ok {
+			return dir
+		}
+	}
+	return ""
 }
 
-// GetNerdctlVersion gets the nerdctl version from the subject. If the subject is neither "nerdctl" nor "finch", it will return an error.
-func (o *Option) GetNerdctlVersion() (string, error) {
-	execName := filepath.Base(o.subject[0])
-	switch execName {
-	case "nerdctl":
-		//nolint:gosec // G204 is not an issue because subject is fully controlled by the user.
-		versionBytes, err := exec.Command(o.subject[0], "--version").Output()
-		if err != nil {
-			return "", fmt.Errorf("failed to run nerdctl --version: %w", err)
+// ImageCatalog returns the images that override the default images used by the tests.
+// It returns an empty catalog if no image is overridden.
+func (o *Option) ImageCatalog() ImageCatalog {
+	if value, exists := o.features[imageCatalog]; exists {
+		if c, ok := value.(ImageCatalog); ok {
+			return c
 		}
-		version, err := getNerdctlVersionMatch(nerdctlVersionRegex, string(versionBytes))
-		if err != nil {
-			return "", err
+	}
+	return ImageCatalog{}
+}
+
+// UsesInProcessRegistry is used by tests to check if the registries needed by the tests
+// should be served from the test binary instead of running as containers.
+func (o *Option) UsesInProcessRegistry() bool {
+	if value, exists := o.features[inProcessRegistry]; exists {
+		if boolValue, ok := value.(bool); ok {
+			return boolValue
 		}
-		return version, nil
-	case "finch":
-		//nolint:gosec // G204 is not an issue because subject is fully controlled by the user.
-		versionBytes, err := exec.Command(o.subject[0], "version").Output()
-		if err != nil {
-			return "", fmt.Errorf("failed to run nerdctl --version: %w", err)
+	}
+	return false
+}
+
+// CleanupLabel returns the label, in the format of key=value, that the cleanup functions in the command package
+// (e.g., command.RemoveAll) are scoped to. An empty string means that they remove all objects.
+// See WithLabelScopedCleanup for more details.
+func (o *Option) CleanupLabel() string {
+	if value, exists := o.features[labelScopedCleanup]; exists {
+		if boolValue, ok := value.(bool); ok && boolValue {
+			return SuiteLabel + "=true"
 		}
-		version, err := getNerdctlVersionMatch(finchNerdctlVersionRegex, string(versionBytes))
-		if err != nil {
-			return "", err
+	}
+	return ""
+}
+
+// Transcript returns the path to the transcript that the commands are recorded to.
+// An empty string means that the commands are not recorded. See WithTranscript for more details.
+func (o *Option) Transcript() string {
+	if value, exists := o.features[transcriptPath]; exists {
+		if path, ok := value.(string); ok {
+			return path
 		}
-		return version, nil
-	default:
-		return "", fmt.Errorf("unsupported subject %s", execName)
 	}
+	return ""
 }
 
-func getNerdctlVersionMatch(nerdctlVersionRegexp *regexp.Regexp, versionOutput string) (string, error) {
-	matches := nerdctlVersionRegexp.FindStringSubmatch(versionOutput)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("failed to parse nerdctl version from: %s", versionOutput)
+// SubjectPath translates hostPath into the path of the same file where the subject runs (see WithPathMapper).
+//
+// Use it wherever a test compares a host path handed to the subject with what the subject reports,
+// e.g., the source of a bind mount, or needs the path as seen by the subject, e.g., as a path inside a container.
+func (o *Option) SubjectPath(hostPath string) string {
+	if value, exists := o.features[pathMapper]; exists {
+		if m, ok := value.(PathMapper); ok {
+			return m.MapPath(hostPath)
+		}
 	}
-	return matches[1], nil
+	return defaultPathMapper().MapPath(hostPath)
+}
+
+// Subject returns the subject stored in the option.
+func (o *Option) Subject() []string {
+	return o.subject
 }
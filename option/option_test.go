@@ -3,7 +3,10 @@
 
 package option
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestSupportsEnvVarPassthrough(t *testing.T) {
 	t.Parallel()
@@ -114,3 +117,130 @@ func TestNerdctlVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestImageArchiveDir(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		mods []Modifier
+		want string
+	}{
+		{
+			name: "IsEmptyByDefault",
+			mods: []Modifier{},
+			want: "",
+		},
+		{
+			name: "IsSetByModifier",
+			mods: []Modifier{
+				WithImageArchiveDir("/tmp/images"),
+			},
+			want: "/tmp/images",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			uut, err := New([]string{"nerdctl"}, test.mods...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := uut.ImageArchiveDir(); got != test.want {
+				t.Fatalf("expected ImageArchiveDir to be %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	o, err := New([]string{"nerdctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if labels := o.Labels(); len(labels) != 0 {
+		t.Fatalf("expected no labels, got %v", labels)
+	}
+
+	o.SetLabel("b", "2")
+	o.SetLabel("a", "1")
+	o.SetLabel("b", "3")
+	if labels := strings.Join(o.Labels(), ","); labels != "a=1,b=3" {
+		t.Fatalf("unexpected labels: %s", labels)
+	}
+
+	o.DeleteLabel("a")
+	o.DeleteLabel("nonexistent")
+	if labels := strings.Join(o.Labels(), ","); labels != "b=3" {
+		t.Fatalf("unexpected labels: %s", labels)
+	}
+}
+
+func TestProtect(t *testing.T) {
+	t.Parallel()
+
+	o, err := New([]string{"nerdctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.IsProtected("ctr") {
+		t.Fatal("expected ctr not to be protected")
+	}
+	o.Protect("ctr", "img")
+	if !o.IsProtected("ctr") || !o.IsProtected("img") {
+		t.Fatal("expected ctr and img to be protected")
+	}
+	o.Unprotect("ctr")
+	if o.IsProtected("ctr") || !o.IsProtected("img") {
+		t.Fatal("expected only img to be protected")
+	}
+}
+
+func TestSuspendCleanup(t *testing.T) {
+	t.Parallel()
+
+	o, err := New([]string{"nerdctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.IsCleanupSuspended() {
+		t.Fatal("expected the cleanup not to be suspended")
+	}
+	o.SuspendCleanup()
+	if !o.IsCleanupSuspended() {
+		t.Fatal("expected the cleanup to be suspended")
+	}
+	o.ResumeCleanup()
+	if o.IsCleanupSuspended() {
+		t.Fatal("expected the cleanup to be resumed")
+	}
+}
+
+func TestLabelScopedCleanup(t *testing.T) {
+	t.Parallel()
+
+	o, err := New([]string{"nerdctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label := o.CleanupLabel(); label != "" {
+		t.Fatalf("expected no cleanup label, got %s", label)
+	}
+
+	o, err = New([]string{"nerdctl"}, WithLabelScopedCleanup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SuiteLabel + "=true"
+	if label := o.CleanupLabel(); label != want {
+		t.Fatalf("expected cleanup label %s, got %s", want, label)
+	}
+	if labels := o.Labels(); len(labels) != 1 || labels[0] != want {
+		t.Fatalf("expected labels [%s], got %v", want, labels)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// PathMapper translates a path on the host that runs the tests into the path of the same file where the subject runs,
+// e.g., in the VM that runs the containers. The tests use it (see Option.SubjectPath) to know how the subject reports
+// the host paths handed to it, e.g., the source of a bind mount.
+type PathMapper interface {
+	MapPath(hostPath string) string
+}
+
+// IdentityPathMapper maps every path to itself. It's the default PathMapper on the platforms other than Windows,
+// where the subject sees the host paths as they are (e.g., Lima mounts the home directory at the same path).
+type IdentityPathMapper struct{}
+
+var _ PathMapper = IdentityPathMapper{}
+
+// MapPath implements PathMapper.
+func (IdentityPathMapper) MapPath(hostPath string) string {
+	return hostPath
+}
+
+// WSLPathMapper maps Windows paths to the paths where WSL mounts the drives, e.g., C:\Users\foo to /mnt/c/Users/foo.
+// It's the default PathMapper on Windows.
+type WSLPathMapper struct{}
+
+var _ PathMapper = WSLPathMapper{}
+
+// MapPath implements PathMapper.
+func (WSLPathMapper) MapPath(hostPath string) string {
+	p, err := filepath.Abs(filepath.Clean(hostPath))
+	if err != nil {
+		p = filepath.Clean(hostPath)
+	}
+	return wslPath(p)
+}
+
+// wslPath maps an absolute Windows path to the path where WSL mounts its drive.
+// Unlike MapPath, it does not depend on the path conventions of the current platform.
+func wslPath(p string) string {
+	if len(p) < 2 || p[1] != ':' {
+		return p
+	}
+	drive := strings.ToLower(p[:1])
+	return path.Join("/", "mnt", drive, strings.ReplaceAll(p[2:], `\`, "/"))
+}
+
+// MountPrefixes maps the paths under each of the host directories to the paths under the directory that it's mounted to
+// where the subject runs. The mapping with the longest matching host directory wins,
+// and the paths that are not under any of the host directories are mapped to themselves.
+type MountPrefixes []PathMapping
+
+var _ PathMapper = MountPrefixes{}
+
+// MapPath implements PathMapper.
+func (m MountPrefixes) MapPath(hostPath string) string {
+	p := filepath.Clean(hostPath)
+	mapping, ok := m.mappingOf(p)
+	if !ok {
+		return hostPath
+	}
+	return mapping.Target + filepath.ToSlash(strings.TrimPrefix(p, filepath.Clean(mapping.Host)))
+}
+
+// mappingOf returns the mapping of the longest host directory that p is under.
+func (m MountPrefixes) mappingOf(p string) (PathMapping, bool) {
+	var found PathMapping
+	ok := false
+	for _, mapping := range m {
+		host := filepath.Clean(mapping.Host)
+		under := p == host || strings.HasPrefix(p, strings.TrimSuffix(host, string(filepath.Separator))+string(filepath.Separator))
+		if under && len(host) > len(filepath.Clean(found.Host)) {
+			found, ok = mapping, true
+		}
+	}
+	return found, ok
+}
+
+func defaultPathMapper() PathMapper {
+	if runtime.GOOS == "windows" {
+		return WSLPathMapper{}
+	}
+	return IdentityPathMapper{}
+}
@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSubjectPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mods     []Modifier
+		hostPath string
+		want     string
+	}{
+		{
+			name:     "MountPrefix",
+			mods:     []Modifier{WithMountPrefix("/home/user", "/host-home")},
+			hostPath: "/home/user/dir/file",
+			want:     "/host-home/dir/file",
+		},
+		{
+			name:     "LongestMountPrefix",
+			mods:     []Modifier{WithMountPrefix("/home/user", "/host-home"), WithMountPrefix("/home/user/mnt", "/mnt")},
+			hostPath: "/home/user/mnt/data",
+			want:     "/mnt/data",
+		},
+		{
+			name:     "MountPrefixItself",
+			mods:     []Modifier{WithMountPrefix("/home/user/", "/host-home")},
+			hostPath: "/home/user",
+			want:     "/host-home",
+		},
+		{
+			name:     "NotUnderMountPrefix",
+			mods:     []Modifier{WithMountPrefix("/home/user", "/host-home")},
+			hostPath: "/home/username/dir",
+			want:     "/home/username/dir",
+		},
+		{
+			name:     "Identity",
+			mods:     []Modifier{WithPathMapper(IdentityPathMapper{})},
+			hostPath: "/home/user/dir",
+			want:     "/home/user/dir",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			o, err := New([]string{"nerdctl"}, test.mods...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := o.SubjectPath(test.hostPath); got != test.want {
+				t.Fatalf("expected %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestWSLPath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`C:\Users\foo\dir`: "/mnt/c/Users/foo/dir",
+		`C:\Users\foo\`:    "/mnt/c/Users/foo",
+		`D:\`:              "/mnt/d",
+		`E:/data/x`:        "/mnt/e/data/x",
+		"/home/foo":        "/home/foo",
+	}
+	for hostPath, want := range tests {
+		if got := wslPath(hostPath); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	}
+}
+
+func TestWSLPathMapper(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS != "windows" {
+		t.Skip("Windows paths are only recognized on Windows")
+	}
+	tests := map[string]string{
+		`C:\Users\foo\dir`: "/mnt/c/Users/foo/dir",
+		`D:\`:              "/mnt/d",
+	}
+	for hostPath, want := range tests {
+		if got := (WSLPathMapper{}).MapPath(hostPath); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PathMapping maps a directory on the host that runs the tests to the path of the same directory where the subject runs,
+// e.g., the directory that it's mounted to in a container. See MountPrefixes for more details.
+type PathMapping struct {
+	Host   string
+	Target string
+}
+
+// ParsePathMappings parses path mappings in the format of host=target separated by commas,
+// e.g., /home/user=/host-home,/tmp=/host-tmp.
+func ParsePathMappings(s string) ([]PathMapping, error) {
+	var mappings []PathMapping
+	for _, m := range strings.Split(s, ",") {
+		if m == "" {
+			continue
+		}
+		host, target, ok := strings.Cut(m, "=")
+		if !ok || host == "" || target == "" {
+			return nil, fmt.Errorf("invalid path mapping %q, must be in the format of host=target", m)
+		}
+		mappings = append(mappings, PathMapping{Host: filepath.Clean(host), Target: target})
+	}
+	return mappings, nil
+}
+
+// PrefixTransport runs the test subject by wrapping it with a prefix command, e.g., to run it inside a container
+// (see ContainerTransport) or a namespace (see NamespaceTransport).
+//
+// The host paths in the arguments of the subject (e.g., a build context or the source of a bind mount) are translated
+// with PathMappings, so the directories that the tests create files in (i.e., the home directory of the current user,
+// see the ffs package) must be shared with the subject, e.g., by mounting them into the container.
+type PrefixTransport struct {
+	// Prefix is the command that the subject is appended to, e.g., ["docker", "exec", "-i", "dev-box"].
+	Prefix []string
+	// TerminalPrefix is the command that the subject is appended to when it's run in a terminal,
+	// e.g., ["docker", "exec", "-it", "dev-box"]. If it's empty, the subject cannot be run in a terminal.
+	TerminalPrefix []string
+	// PathMappings translates the host paths in the arguments of the subject.
+	// The paths that are not under any of the mappings are passed as is.
+	PathMappings []PathMapping
+}
+
+var _ TerminalTransport = PrefixTransport{}
+
+// ContainerTransport returns a transport that runs the subject inside a running container with `<runtime> exec`,
+// where runtime is a container CLI on the host (e.g., docker).
+func ContainerTransport(runtime, container string, mappings ...PathMapping) PrefixTransport {
+	return PrefixTransport{
+		Prefix:         []string{runtime, "exec", "-i", container},
+		TerminalPrefix: []string{runtime, "exec", "-it", container},
+		PathMappings:   mappings,
+	}
+}
+
+// NamespaceTransport returns a transport that runs the subject inside all the namespaces of the process specified by pid
+// with nsenter.
+func NamespaceTransport(pid int, mappings ...PathMapping) PrefixTransport {
+	prefix := []string{"nsenter", "--target", strconv.Itoa(pid), "--all"}
+	return PrefixTransport{Prefix: prefix, TerminalPrefix: prefix, PathMappings: mappings}
+}
+
+// Command implements Transport. The environment variables are passed through env(1) so that they reach the subject
+// even if the prefix command does not pass its own environment to it.
+func (t PrefixTransport) Command(name string, args []string, env []string) *exec.Cmd {
+	return t.command(t.Prefix, name, args, env)
+}
+
+// TerminalCommand implements TerminalTransport. It runs the subject with TerminalPrefix.
+func (t PrefixTransport) TerminalCommand(name string, args []string, env []string) (*exec.Cmd, error) {
+	if len(t.TerminalPrefix) == 0 {
+		return nil, fmt.Errorf("%q does not forward a terminal to the subject", strings.Join(t.Prefix, " "))
+	}
+	return t.command(t.TerminalPrefix, name, args, env), nil
+}
+
+func (t PrefixTransport) command(prefix []string, name string, args []string, env []string) *exec.Cmd {
+	cmdArgs := append([]string{}, prefix[1:]...)
+	if len(env) > 0 {
+		cmdArgs = append(append(cmdArgs, "env"), env...)
+	}
+	cmdArgs = append(append(cmdArgs, name), t.translate(args)...)
+	cmd := exec.Command(prefix[0], cmdArgs...) //nolint:gosec // G204 is not an issue because the prefix is fully controlled by the user.
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
+
+// Stage implements Transport. Nothing is copied because the paths must be shared with the subject via PathMappings,
+// so it only checks that p is under one of them.
+func (t PrefixTransport) Stage(p string) error {
+	if len(t.PathMappings) == 0 {
+		return nil
+	}
+	if _, ok := MountPrefixes(t.PathMappings).mappingOf(filepath.Clean(p)); !ok {
+		return fmt.Errorf("%s is not under any of the path mappings, so it's not shared with the subject", p)
+	}
+	return nil
+}
+
+// Unstage implements Transport. It's a no-op because nothing is copied by Stage.
+func (PrefixTransport) Unstage(string) error {
+	return nil
+}
+
+// hostPaths describes where the host paths are in the arguments of a subcommand.
+type hostPaths struct {
+	// flags are the flags whose values are host paths, or contain them (see translateValue).
+	flags []string
+	// positional returns the indexes of the arguments after the subcommand that are host paths.
+	positional func(args []string) []int
+}
+
+var runHostPaths = hostPaths{flags: []string{"-v", "--volume", "--mount", "--env-file", "--cidfile", "--label-file"}}
+
+// subcommandHostPaths maps the subcommands (without the "image", "container" or "builder" group) to where their host paths are.
+// The arguments of the other subcommands and the other arguments of these ones (e.g., the command run in a container)
+// are passed as is.
+var subcommandHostPaths = map[string]hostPaths{
+	"run":     runHostPaths,
+	"create":  runHostPaths,
+	"build":   {flags: []string{"-f", "--file", "-o", "--output", "--secret", "--iidfile"}, positional: lastArg},
+	"compose": {flags: []string{"-f", "--file", "--project-directory", "--env-file"}},
+	"save":    {flags: []string{"-o", "--output"}},
+	"export":  {flags: []string{"-o", "--output"}},
+	"load":    {flags: []string{"-i", "--input"}},
+	"import":  {positional: firstPositionalArg("-c", "--change", "-m", "--message", "--platform")},
+	"cp":      {positional: cpHostArgs},
+}
+
+// lastArg returns the index of the last argument, e.g., the build context.
+func lastArg(args []string) []int {
+	if len(args) == 0 {
+		return nil
+	}
+	return []int{len(args) - 1}
+}
+
+// firstPositionalArg returns a function that returns the index of the first argument that is neither a flag
+// nor the value of one of valueFlags, e.g., the file to import.
+func firstPositionalArg(valueFlags ...string) func(args []string) []int {
+	return func(args []string) []int {
+		for i := 0; i < len(args); i++ {
+			switch {
+			case slices.Contains(valueFlags, args[i]):
+				i++
+			case !strings.HasPrefix(args[i], "-"):
+				return []int{i}
+			}
+		}
+		return nil
+	}
+}
+
+// cpHostArgs returns the indexes of the arguments of "cp" that are on the host side, i.e., not in the form of container:path.
+func cpHostArgs(args []string) []int {
+	var indexes []int
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") && !strings.Contains(arg, ":") {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// translate replaces the host paths in args with the corresponding target paths.
+//
+// The host paths are only looked for where the subcommand expects them (see subcommandHostPaths), e.g., the sources of
+// the bind mounts (i.e., the left side of -v src:dst and source= or src= in --mount), the build context, the value of
+// -f of "build" and "compose", the file to import and the host side of "cp". The other arguments are passed as is,
+// even if they happen to be the same as a host path (e.g., -v $PWD:$PWD or the arguments of the command run in a container).
+// The subcommand is the first argument that is not a flag, so the global flags must not take a separate value.
+func (t PrefixTransport) translate(args []string) []string {
+	if len(t.PathMappings) == 0 {
+		return args
+	}
+	start := slices.IndexFunc(args, func(arg string) bool { return !strings.HasPrefix(arg, "-") })
+	if start < 0 {
+		return args
+	}
+	subcommand := args[start]
+	if slices.Contains([]string{"image", "container", "builder"}, subcommand) && start+1 < len(args) {
+		start++
+		subcommand = args[start]
+	}
+	paths, ok := subcommandHostPaths[subcommand]
+	if !ok {
+		return args
+	}
+
+	translated := slices.Clone(args)
+	rest := translated[start+1:]
+	if paths.positional != nil {
+		for _, i := range paths.positional(rest) {
+			rest[i] = t.translateValue("", rest[i])
+		}
+	}
+	for i := 0; i < len(rest); i++ {
+		name, value, hasValue := strings.Cut(rest[i], "=")
+		switch {
+		case strings.HasPrefix(name, "-") && hasValue && slices.Contains(paths.flags, name):
+			rest[i] = name + "=" + t.translateValue(name, value)
+		case slices.Contains(paths.flags, rest[i]) && i+1 < len(rest):
+			rest[i+1] = t.translateValue(rest[i], rest[i+1])
+			i++
+		}
+	}
+	return translated
+}
+
+// translateValue translates the host paths in value, which is either the value of flag or a positional argument
+// if flag is empty.
+func (t PrefixTransport) translateValue(flag, value string) string {
+	mapper := MountPrefixes(t.PathMappings)
+	switch flag {
+	case "-v", "--volume":
+		src, rest, ok := strings.Cut(value, ":")
+		if ok && filepath.IsAbs(src) {
+			return mapper.MapPath(src) + ":" + rest
+		}
+		return value
+	case "--mount", "--secret":
+		return t.translateFields(value, "source", "src")
+	case "-o", "--output":
+		if strings.Contains(value, "=") {
+			return t.translateFields(value, "dest")
+		}
+	}
+	if filepath.IsAbs(value) {
+		return mapper.MapPath(value)
+	}
+	return value
+}
+
+// translateFields translates the host paths in the comma-separated key=value fields of value whose keys are one of keys,
+// e.g., source=/path in the value of --mount.
+func (t PrefixTransport) translateFields(value string, keys ...string) string {
+	fields := strings.Split(value, ",")
+	for i, field := range fields {
+		key, p, ok := strings.Cut(field, "=")
+		if ok && slices.Contains(keys, key) && filepath.IsAbs(p) {
+			fields[i] = key + "=" + MountPrefixes(t.PathMappings).MapPath(p)
+		}
+	}
+	return strings.Join(fields, ",")
+}
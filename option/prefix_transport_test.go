@@ -0,0 +1,196 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParsePathMappings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    []PathMapping
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "/home/user=/host-home", want: []PathMapping{{Host: "/home/user", Target: "/host-home"}}},
+		{
+			input: "/home/user/=/host-home,/tmp=/host-tmp",
+			want:  []PathMapping{{Host: "/home/user", Target: "/host-home"}, {Host: "/tmp", Target: "/host-tmp"}},
+		},
+		{input: "/home/user", wantErr: true},
+		{input: "=/host-home", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePathMappings(test.input)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPrefixTransportCommand(t *testing.T) {
+	t.Parallel()
+
+	mappings := []PathMapping{{Host: "/home/user", Target: "/host-home"}, {Host: "/home/user/mnt", Target: "/mnt"}}
+	tests := []struct {
+		name      string
+		transport PrefixTransport
+		env       []string
+		args      []string
+		wantArgs  []string
+	}{
+		{
+			name:      "Container",
+			transport: ContainerTransport("docker", "dev-box", mappings...),
+			args:      []string{"build", "/home/user/ctx"},
+			wantArgs:  []string{"docker", "exec", "-i", "dev-box", "nerdctl", "build", "/host-home/ctx"},
+		},
+		{
+			name:      "ContainerWithEnv",
+			transport: ContainerTransport("docker", "dev-box"),
+			env:       []string{"FOO=bar"},
+			args:      []string{"run", "/home/user/ctx"},
+			wantArgs:  []string{"docker", "exec", "-i", "dev-box", "env", "FOO=bar", "nerdctl", "run", "/home/user/ctx"},
+		},
+		{
+			name:      "Namespace",
+			transport: NamespaceTransport(42, mappings...),
+			args: []string{
+				"run", "--env-file=/home/user/env", "-v", "/home/user/mnt/data:/data",
+				"--mount", "type=bind,source=/home/user/x,target=/home/user/x", "/home/username", "alpine",
+			},
+			wantArgs: []string{
+				"nsenter", "--target", "42", "--all", "nerdctl",
+				"run", "--env-file=/host-home/env", "-v", "/mnt/data:/data",
+				"--mount", "type=bind,source=/host-home/x,target=/home/user/x", "/home/username", "alpine",
+			},
+		},
+		{
+			name:      "ContainerPaths",
+			transport: NamespaceTransport(42, mappings...),
+			args: []string{
+				"run", "-v", "/home/user/pwd:/home/user/pwd", "--volume=/home/user/pwd:/home/user/pwd:ro", "-v", "/home/user/anon",
+				"-w", "/home/user/pwd", "--workdir=/home/user/pwd", "--mount=type=bind,src=/home/user/x,dst=/home/user/x",
+				"--cidfile", "/home/user/cid", "alpine",
+			},
+			wantArgs: []string{
+				"nsenter", "--target", "42", "--all", "nerdctl",
+				"run", "-v", "/host-home/pwd:/home/user/pwd", "--volume=/host-home/pwd:/home/user/pwd:ro", "-v", "/home/user/anon",
+				"-w", "/home/user/pwd", "--workdir=/home/user/pwd", "--mount=type=bind,src=/host-home/x,dst=/home/user/x",
+				"--cidfile", "/host-home/cid", "alpine",
+			},
+		},
+		{
+			name:      "ContainerArgs",
+			transport: NamespaceTransport(42, PathMapping{Host: "/tmp", Target: "/host-tmp"}),
+			args:      []string{"run", "img", "cat", "/tmp/x"},
+			wantArgs:  []string{"nsenter", "--target", "42", "--all", "nerdctl", "run", "img", "cat", "/tmp/x"},
+		},
+		{
+			name:      "Build",
+			transport: NamespaceTransport(42, mappings...),
+			args: []string{
+				"image", "build", "-f", "/home/user/ctx/Dockerfile", "--secret", "id=s,src=/home/user/secret",
+				"--output=type=local,dest=/home/user/out", "-t", "/home/user/tag", "/home/user/ctx",
+			},
+			wantArgs: []string{
+				"nsenter", "--target", "42", "--all", "nerdctl",
+				"image", "build", "-f", "/host-home/ctx/Dockerfile", "--secret", "id=s,src=/host-home/secret",
+				"--output=type=local,dest=/host-home/out", "-t", "/home/user/tag", "/host-home/ctx",
+			},
+		},
+		{
+			name:      "Compose",
+			transport: NamespaceTransport(42, mappings...),
+			args:      []string{"compose", "-f", "/home/user/compose.yaml", "run", "svc", "ls", "/home/user"},
+			wantArgs: []string{
+				"nsenter", "--target", "42", "--all", "nerdctl",
+				"compose", "-f", "/host-home/compose.yaml", "run", "svc", "ls", "/home/user",
+			},
+		},
+		{
+			name:      "Save",
+			transport: NamespaceTransport(42, mappings...),
+			args:      []string{"save", "-o", "/home/user/img.tar", "alpine"},
+			wantArgs:  []string{"nsenter", "--target", "42", "--all", "nerdctl", "save", "-o", "/host-home/img.tar", "alpine"},
+		},
+		{
+			name:      "Load",
+			transport: NamespaceTransport(42, mappings...),
+			args:      []string{"load", "--input=/home/user/img.tar"},
+			wantArgs:  []string{"nsenter", "--target", "42", "--all", "nerdctl", "load", "--input=/host-home/img.tar"},
+		},
+		{
+			name:      "Import",
+			transport: NamespaceTransport(42, mappings...),
+			args:      []string{"import", "--change", "WORKDIR /home/user", "/home/user/fs.tar", "img"},
+			wantArgs: []string{
+				"nsenter", "--target", "42", "--all", "nerdctl",
+				"import", "--change", "WORKDIR /home/user", "/host-home/fs.tar", "img",
+			},
+		},
+		{
+			name:      "Cp",
+			transport: NamespaceTransport(42, mappings...),
+			args:      []string{"container", "cp", "-L", "/home/user/file", "ctr:/home/user/file"},
+			wantArgs: []string{
+				"nsenter", "--target", "42", "--all", "nerdctl",
+				"container", "cp", "-L", "/host-home/file", "ctr:/home/user/file",
+			},
+		},
+		{
+			name:      "Filter",
+			transport: NamespaceTransport(42, mappings...),
+			args:      []string{"ps", "--filter", "volume=/home/user/pwd", "--filter=volume=/home/user/pwd"},
+			wantArgs: []string{
+				"nsenter", "--target", "42", "--all", "nerdctl",
+				"ps", "--filter", "volume=/home/user/pwd", "--filter=volume=/home/user/pwd",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := test.transport.Command("nerdctl", test.args, test.env)
+			if !reflect.DeepEqual(cmd.Args, test.wantArgs) {
+				t.Fatalf("expected args %q, got %q", test.wantArgs, cmd.Args)
+			}
+			for _, e := range test.env {
+				if !slices.Contains(cmd.Env, e) {
+					t.Fatalf("expected %s in env", e)
+				}
+			}
+		})
+	}
+}
+
+func TestPrefixTransportStage(t *testing.T) {
+	t.Parallel()
+
+	transport := ContainerTransport("docker", "dev-box", PathMapping{Host: "/home/user", Target: "/host-home"})
+	if err := transport.Stage("/home/user/ctx"); err != nil {
+		t.Fatalf("expected a mapped path to be staged, got %v", err)
+	}
+	if err := transport.Stage("/tmp/ctx"); err == nil {
+		t.Fatal("expected staging an unmapped path to fail")
+	}
+	if err := ContainerTransport("docker", "dev-box").Stage("/tmp/ctx"); err != nil {
+		t.Fatalf("expected staging to be a no-op without path mappings, got %v", err)
+	}
+}
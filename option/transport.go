@@ -0,0 +1,196 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Transport runs the test subject, either on the host that runs the tests (LocalTransport) or on another one (SSHTransport).
+type Transport interface {
+	// Command returns the command that runs name with args.
+	// env are the environment variables to be set on top of the ones that the subject inherits.
+	Command(name string, args []string, env []string) *exec.Cmd
+	// Stage makes the local file or directory specified by the absolute path available to the subject at the same path.
+	Stage(path string) error
+	// Unstage removes the copy of the file or directory made by Stage, if any.
+	Unstage(path string) error
+}
+
+// TerminalTransport is a Transport that can forward a terminal to the test subject, i.e., the subject sees a terminal
+// when the command returned by TerminalCommand is started in a pseudo-terminal (e.g., for `run -it`).
+type TerminalTransport interface {
+	Transport
+	// TerminalCommand is like Command, but it returns an error if the terminal cannot be forwarded to the subject.
+	TerminalCommand(name string, args []string, env []string) (*exec.Cmd, error)
+}
+
+// LocalTransport runs the test subject on the host that runs the tests. It's the default Transport.
+type LocalTransport struct{}
+
+var _ TerminalTransport = LocalTransport{}
+
+// Command implements Transport.
+func (LocalTransport) Command(name string, args []string, env []string) *exec.Cmd {
+	cmd := exec.Command(name, args...) //nolint:gosec // G204 is not an issue because name is fully controlled by the user.
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
+
+// TerminalCommand implements TerminalTransport. The subject inherits the terminal as is.
+func (t LocalTransport) TerminalCommand(name string, args []string, env []string) (*exec.Cmd, error) {
+	return t.Command(name, args, env), nil
+}
+
+// Stage implements Transport. It's a no-op because the subject shares the file system with the tests.
+func (LocalTransport) Stage(string) error {
+	return nil
+}
+
+// Unstage implements Transport. It's a no-op because nothing is copied by Stage.
+func (LocalTransport) Unstage(string) error {
+	return nil
+}
+
+// SSHTransport runs the test subject on a Linux host reachable over SSH, e.g., a VM or a lab box.
+//
+// The files and directories used by the tests (e.g., build contexts) are staged by copying them to the same absolute paths
+// on the remote host, so the home directory of the current user should exist and be writable on the remote host too.
+// The staged copies are removed by Unstage, and the files written by the subject (e.g., the output of "save") are not copied back.
+type SSHTransport struct {
+	// Destination is the remote host in a format accepted by ssh, e.g., user@host or an alias in the ssh config.
+	Destination string
+	// Args are additional arguments passed to ssh before Destination, e.g., ["-p", "2222", "-i", "/path/to/key"].
+	Args []string
+	// Binary is the ssh binary. It defaults to "ssh".
+	Binary string
+}
+
+var _ TerminalTransport = SSHTransport{}
+
+// Command implements Transport.
+func (t SSHTransport) Command(name string, args []string, env []string) *exec.Cmd {
+	return t.ssh(remoteCommand(name, args, env))
+}
+
+// TerminalCommand implements TerminalTransport. It forces ssh to allocate a terminal on the remote host with -tt.
+func (t SSHTransport) TerminalCommand(name string, args []string, env []string) (*exec.Cmd, error) {
+	return t.ssh(remoteCommand(name, args, env), "-tt"), nil
+}
+
+// remoteCommand returns the command line that runs name with args and env on the remote host.
+func remoteCommand(name string, args []string, env []string) string {
+	remote := append([]string{name}, args...)
+	if len(env) > 0 {
+		remote = append(append([]string{"env"}, env...), remote...)
+	}
+	return shellJoin(remote)
+}
+
+// Stage implements Transport.
+func (t SSHTransport) Stage(p string) error {
+	if !filepath.IsAbs(p) {
+		return fmt.Errorf("%s is not an absolute path", p)
+	}
+	var buf bytes.Buffer
+	if err := writeTar(&buf, p); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", p, err)
+	}
+	parent := path.Dir(filepath.ToSlash(p))
+	cmd := t.ssh(fmt.Sprintf("mkdir -p %s && tar -C %s -xf -", shellQuote(parent), shellQuote(parent)))
+	cmd.Stdin = &buf
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage %s to %s: %w: %s", p, t.Destination, err, out)
+	}
+	return nil
+}
+
+// Unstage implements Transport. It removes the copy of p on the remote host.
+func (t SSHTransport) Unstage(p string) error {
+	if !filepath.IsAbs(p) {
+		return fmt.Errorf("%s is not an absolute path", p)
+	}
+	if out, err := t.ssh("rm -rf -- " + shellQuote(filepath.ToSlash(p))).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to unstage %s from %s: %w: %s", p, t.Destination, err, out)
+	}
+	return nil
+}
+
+func (t SSHTransport) ssh(remoteCommand string, sshArgs ...string) *exec.Cmd {
+	binary := t.Binary
+	if binary == "" {
+		binary = "ssh"
+	}
+	args := append(append(append([]string{}, sshArgs...), t.Args...), t.Destination, "--", remoteCommand)
+	return exec.Command(binary, args...) //nolint:gosec // G204 is not an issue because the arguments are fully controlled by the user.
+}
+
+// writeTar writes a tar archive to w which contains the file or directory at p with its base name as the root.
+func writeTar(w io.Writer, p string) error {
+	tw := tar.NewWriter(w)
+	root := filepath.Dir(p)
+	err := filepath.WalkDir(p, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(filepath.Clean(file))
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck // The file is only read.
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// shellJoin quotes each of args for a POSIX shell and joins them with spaces.
+func shellJoin(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,270 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestNewCmdWithTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mods      []Modifier
+		wantPath  string
+		wantArgs  []string
+		wantInEnv string
+	}{
+		{
+			name:      "Local",
+			mods:      []Modifier{Env([]string{"FOO=bar"})},
+			wantPath:  "nerdctl",
+			wantArgs:  []string{"nerdctl", "--namespace", "test", "run", "it's"},
+			wantInEnv: "FOO=bar",
+		},
+		{
+			name: "SSH",
+			mods: []Modifier{
+				Env([]string{"FOO=bar baz"}),
+				WithTransport(SSHTransport{Destination: "user@host", Args: []string{"-p", "2222"}}),
+			},
+			wantPath: "ssh",
+			wantArgs: []string{
+				"ssh", "-p", "2222", "user@host", "--",
+				`'env' 'FOO=bar baz' 'nerdctl' '--namespace' 'test' 'run' 'it'\''s'`,
+			},
+		},
+		{
+			name:     "SSHWithoutEnv",
+			mods:     []Modifier{WithTransport(SSHTransport{Destination: "host", Binary: "/usr/bin/ssh"})},
+			wantPath: "/usr/bin/ssh",
+			wantArgs: []string{"/usr/bin/ssh", "host", "--", `'nerdctl' '--namespace' 'test' 'run' 'it'\''s'`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			o, err := New([]string{"nerdctl", "--namespace", "test"}, test.mods...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cmd := o.NewCmd("run", "it's")
+			if filepath.Base(cmd.Path) != filepath.Base(test.wantPath) {
+				t.Fatalf("expected path %s, got %s", test.wantPath, cmd.Path)
+			}
+			if !reflect.DeepEqual(cmd.Args, test.wantArgs) {
+				t.Fatalf("expected args %q, got %q", test.wantArgs, cmd.Args)
+			}
+			if test.wantInEnv != "" && !slices.Contains(cmd.Env, test.wantInEnv) {
+				t.Fatalf("expected %s in env", test.wantInEnv)
+			}
+		})
+	}
+}
+
+// commandOnlyTransport is a transport that cannot forward a terminal to the subject.
+type commandOnlyTransport struct{}
+
+func (commandOnlyTransport) Command(name string, args []string, env []string) *exec.Cmd {
+	return LocalTransport{}.Command(name, args, env)
+}
+
+func (commandOnlyTransport) Stage(string) error {
+	return nil
+}
+
+func (commandOnlyTransport) Unstage(string) error {
+	return nil
+}
+
+func TestNewTerminalCmd(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mods     []Modifier
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name:     "Local",
+			wantArgs: []string{"nerdctl", "--namespace", "test", "run", "-it"},
+		},
+		{
+			name:     "SSH",
+			mods:     []Modifier{WithTransport(SSHTransport{Destination: "host", Args: []string{"-p", "2222"}})},
+			wantArgs: []string{"ssh", "-tt", "-p", "2222", "host", "--", `'nerdctl' '--namespace' 'test' 'run' '-it'`},
+		},
+		{
+			name:     "Container",
+			mods:     []Modifier{WithTransport(ContainerTransport("docker", "dev-box"))},
+			wantArgs: []string{"docker", "exec", "-it", "dev-box", "nerdctl", "--namespace", "test", "run", "-it"},
+		},
+		{
+			name:     "Namespace",
+			mods:     []Modifier{WithTransport(NamespaceTransport(42))},
+			wantArgs: []string{"nsenter", "--target", "42", "--all", "nerdctl", "--namespace", "test", "run", "-it"},
+		},
+		{
+			name:    "PrefixWithoutTerminalPrefix",
+			mods:    []Modifier{WithTransport(PrefixTransport{Prefix: []string{"docker", "exec", "-i", "dev-box"}})},
+			wantErr: true,
+		},
+		{
+			name:    "NotTerminalTransport",
+			mods:    []Modifier{WithTransport(commandOnlyTransport{})},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			o, err := New([]string{"nerdctl", "--namespace", "test"}, test.mods...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cmd, err := o.NewTerminalCmd("run", "-it")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("expected error: %t, got %v", test.wantErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(cmd.Args, test.wantArgs) {
+				t.Fatalf("expected args %q, got %q", test.wantArgs, cmd.Args)
+			}
+		})
+	}
+}
+
+type stageRecorder struct {
+	LocalTransport
+	staged   []string
+	unstaged []string
+}
+
+func (r *stageRecorder) Stage(p string) error {
+	if p == "fail" {
+		return errors.New("failed")
+	}
+	r.staged = append(r.staged, p)
+	return nil
+}
+
+func (r *stageRecorder) Unstage(p string) error {
+	r.unstaged = append(r.unstaged, p)
+	return nil
+}
+
+func TestStage(t *testing.T) {
+	t.Parallel()
+
+	o, err := New([]string{"nerdctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Stage("/any"); err != nil {
+		t.Fatalf("expected staging to be a no-op locally, got %v", err)
+	}
+
+	r := &stageRecorder{}
+	o, err = New([]string{"nerdctl"}, WithTransport(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Stage("/a", "/b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Stage("/a"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.staged, []string{"/a", "/b"}) {
+		t.Fatalf("expected each path to be staged once, got %v", r.staged)
+	}
+	if err := o.Stage("fail"); err == nil {
+		t.Fatal("expected staging to fail")
+	}
+	if !reflect.DeepEqual(o.StagedPaths(), []string{"/a", "/b"}) {
+		t.Fatalf("unexpected staged paths %v", o.StagedPaths())
+	}
+
+	if err := o.Unstage(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.unstaged, []string{"/a", "/b"}) {
+		t.Fatalf("unexpected unstaged paths %v", r.unstaged)
+	}
+	if len(o.StagedPaths()) != 0 {
+		t.Fatalf("expected the staged paths to be forgotten, got %v", o.StagedPaths())
+	}
+	if err := o.Stage("/a"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.staged, []string{"/a", "/b", "/a"}) {
+		t.Fatalf("expected the path to be staged again after unstaging, got %v", r.staged)
+	}
+
+	if err := (SSHTransport{Destination: "host"}).Stage("relative"); err == nil {
+		t.Fatal("expected staging a relative path to fail")
+	}
+	if err := (SSHTransport{Destination: "host"}).Unstage("relative"); err == nil {
+		t.Fatal("expected unstaging a relative path to fail")
+	}
+}
+
+func TestWriteTar(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "context")
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "file"), []byte("content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeTar(&buf, dir); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[hdr.Name] = string(content)
+	}
+	want := map[string]string{
+		"context":            "",
+		"context/Dockerfile": "FROM alpine",
+		"context/sub":        "",
+		"context/sub/file":   "content",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The components whose versions are reported by GetComponentVersions.
+const (
+	ComponentNerdctl    = "nerdctl"
+	ComponentContainerd = "containerd"
+	ComponentRunc       = "runc"
+	ComponentBuildKit   = "buildkit"
+	ComponentCompose    = "compose"
+	ComponentCNI        = "cni"
+)
+
+var (
+	nerdctlVersionRegex      = regexp.MustCompile(`nerdctl\s+version\s+(\S+)`)
+	finchNerdctlVersionRegex = regexp.MustCompile(`nerdctl:\s+Version:\s+(\S+)`)
+)
+
+// GetNerdctlVersion gets the nerdctl version from the subject. See GetComponentVersions for how it's detected.
+func (o *Option) GetNerdctlVersion() (string, error) {
+	return o.GetComponentVersion(ComponentNerdctl)
+}
+
+// GetComponentVersion gets the version of the component (e.g., ComponentContainerd) from the subject.
+// See GetComponentVersions for how it's detected.
+func (o *Option) GetComponentVersion(component string) (string, error) {
+	versions, err := o.GetComponentVersions()
+	if err != nil {
+		return "", err
+	}
+	version, ok := versions[component]
+	if !ok {
+		return "", fmt.Errorf("the subject does not report the version of %s", component)
+	}
+	return version, nil
+}
+
+// GetComponentVersions gets the versions of nerdctl, containerd, runc, BuildKit and compose from the subject,
+// keyed by ComponentNerdctl, ComponentContainerd, ComponentRunc, ComponentBuildKit and ComponentCompose.
+// The leading "v" of the versions is trimmed. The versions specified with WithComponentVersion take precedence,
+// which is the only way to specify the versions that cannot be detected, e.g., the one of ComponentCNI.
+//
+// The subject is run with its prefix (e.g., "lima nerdctl" or "sudo nerdctl") and its transport (see WithTransport).
+// `version --format "{{json .}}"` is tried first, which is supported by nerdctl and by finch (whose output wraps
+// the one of nerdctl in a "Nerdctl" field). If it fails, only the nerdctl version is parsed
+// from the output of "--version" (e.g., "nerdctl version 1.7.0") or of "version" (e.g., "nerdctl:\n Version: v1.7.0").
+func (o *Option) GetComponentVersions() (map[string]string, error) {
+	versions, err := o.detectComponentVersions()
+	if err != nil {
+		return nil, err
+	}
+	if out, err := o.NewCmd("compose", "version", "--format", "json").Output(); err == nil {
+		var compose struct{ Version string }
+		if err := json.Unmarshal(out, &compose); err == nil && compose.Version != "" {
+			versions[ComponentCompose] = trimVersion(compose.Version)
+		}
+	}
+	if overrides, ok := o.features[componentVersions].(map[string]string); ok {
+		for component, version := range overrides {
+			versions[component] = version
+		}
+	}
+	return versions, nil
+}
+
+func (o *Option) detectComponentVersions() (map[string]string, error) {
+	if out, err := o.NewCmd("version", "--format", "{{json .}}").Output(); err == nil {
+		if versions, err := parseVersionJSON(out); err == nil {
+			return versions, nil
+		}
+	}
+
+	var errs []string
+	for _, attempt := range []struct {
+		args  []string
+		regex *regexp.Regexp
+	}{
+		{args: []string{"--version"}, regex: nerdctlVersionRegex},
+		{args: []string{"version"}, regex: finchNerdctlVersionRegex},
+	} {
+		out, err := o.NewCmd(attempt.args...).Output()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to run %s: %v", strings.Join(attempt.args, " "), err))
+			continue
+		}
+		version, err := getNerdctlVersionMatch(attempt.regex, string(out))
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return map[string]string{ComponentNerdctl: trimVersion(version)}, nil
+	}
+	return nil, fmt.Errorf("failed to get the versions from the subject %q: %s", strings.Join(o.subject, " "), strings.Join(errs, "; "))
+}
+
+// versionJSON is the output of `nerdctl version --format "{{json .}}"`.
+type versionJSON struct {
+	Client struct {
+		Version    string
+		Components []componentJSON
+	}
+	Server *struct {
+		Components []componentJSON
+	}
+	// Nerdctl is the output of nerdctl wrapped by finch.
+	Nerdctl *versionJSON
+}
+
+type componentJSON struct {
+	Name    string
+	Version string
+}
+
+// parseVersionJSON parses the output of `version --format "{{json .}}"` of nerdctl or finch.
+func parseVersionJSON(data []byte) (map[string]string, error) {
+	var v versionJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode the version JSON: %w", err)
+	}
+	if v.Nerdctl != nil {
+		v = *v.Nerdctl
+	}
+	if v.Client.Version == "" {
+		return nil, fmt.Errorf("no client version in the version JSON: %s", data)
+	}
+
+	versions := map[string]string{ComponentNerdctl: trimVersion(v.Client.Version)}
+	components := v.Client.Components
+	if v.Server != nil {
+		components = append(components, v.Server.Components...)
+	}
+	for _, c := range components {
+		name := strings.ToLower(c.Name)
+		if name == "buildctl" {
+			name = ComponentBuildKit
+		}
+		versions[name] = trimVersion(c.Version)
+	}
+	return versions, nil
+}
+
+func trimVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+func getNerdctlVersionMatch(nerdctlVersionRegexp *regexp.Regexp, versionOutput string) (string, error) {
+	matches := nerdctlVersionRegexp.FindStringSubmatch(versionOutput)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("failed to parse nerdctl version from: %s", versionOutput)
+	}
+	return matches[1], nil
+}
@@ -0,0 +1,130 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package option
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestParseVersionJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "Nerdctl",
+			data: `{"Client":{"Version":"v2.0.2","Components":[{"Name":"buildctl","Version":"v0.18.1"}]},` +
+				`"Server":{"Components":[{"Name":"containerd","Version":"v1.7.24"},{"Name":"runc","Version":"1.2.3"}]}}`,
+			want: map[string]string{"nerdctl": "2.0.2", "buildkit": "0.18.1", "containerd": "1.7.24", "runc": "1.2.3"},
+		},
+		{
+			name: "NerdctlWithoutServer",
+			data: `{"Client":{"Version":"v1.7.7"}}`,
+			want: map[string]string{"nerdctl": "1.7.7"},
+		},
+		{
+			name: "Finch",
+			data: `{"Client":{"Version":"v1.4.0"},"Nerdctl":{"Client":{"Version":"v2.0.2"},` +
+				`"Server":{"Components":[{"Name":"containerd","Version":"v1.7.24"}]}}}`,
+			want: map[string]string{"nerdctl": "2.0.2", "containerd": "1.7.24"},
+		},
+		{
+			name:    "NoClientVersion",
+			data:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "NotJSON",
+			data:    "nerdctl version 1.7.7",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseVersionJSON([]byte(test.data))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("expected error: %t, got %v", test.wantErr, err)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetComponentVersions(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake subjects are shell scripts")
+	}
+
+	tests := []struct {
+		name    string
+		script  string
+		mods    []Modifier
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "JSON",
+			script: `[ "$1 $2" = "version --format" ] && ` +
+				`echo '{"Client":{"Version":"v2.0.2"},"Server":{"Components":[{"Name":"runc","Version":"1.2.3"}]}}'`,
+			want: map[string]string{"nerdctl": "2.0.2", "runc": "1.2.3"},
+		},
+		{
+			name: "ComposeAndOverrides",
+			script: `case "$*" in
+				"compose version --format json") echo '{"version":"v2.0.2"}' ;;
+				"--version") echo 'nerdctl version 2.0.2' ;;
+				*) exit 1 ;;
+			esac`,
+			mods: []Modifier{WithComponentVersion(ComponentCNI, "1.6.0"), WithComponentVersion(ComponentNerdctl, "2.0.3")},
+			want: map[string]string{"nerdctl": "2.0.3", "compose": "2.0.2", "cni": "1.6.0"},
+		},
+		{
+			name:   "FallsBackToVersionFlag",
+			script: `[ "$1" = "--version" ] && echo 'nerdctl version 1.7.7'`,
+			want:   map[string]string{"nerdctl": "1.7.7"},
+		},
+		{
+			name:   "FallsBackToFinchVersion",
+			script: `[ "$*" = "version" ] && printf 'Client:\n Version: v1.4.0\nnerdctl:\n Version: v2.0.2\n'`,
+			want:   map[string]string{"nerdctl": "2.0.2"},
+		},
+		{
+			name:    "Fails",
+			script:  "exit 1",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// The subject is prefixed like "lima nerdctl" or "sudo nerdctl".
+			o, err := New([]string{"sh", "-c", test.script, "fake-nerdctl"}, test.mods...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := o.GetComponentVersions()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("expected error: %t, got %v", test.wantErr, err)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
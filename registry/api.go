@@ -0,0 +1,249 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+func (r *Registry) serveBlob(w http.ResponseWriter, req *http.Request, name, digest string) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		r.mu.Lock()
+		blob, exists := r.blobs[digest]
+		r.mu.Unlock()
+		if !exists {
+			writeError(w, http.StatusNotFound, "BLOB_UNKNOWN", fmt.Sprintf("blob %s is unknown to repository %s", digest, name))
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(blob)
+		}
+	case http.MethodDelete:
+		r.mu.Lock()
+		delete(r.blobs, digest)
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", req.Method)
+	}
+}
+
+func (r *Registry) serveUpload(w http.ResponseWriter, req *http.Request, name, id string) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BLOB_UPLOAD_INVALID", err.Error())
+		return
+	}
+	query := req.URL.Query()
+
+	switch {
+	case req.Method == http.MethodPost && id == "":
+		if mount := query.Get("mount"); mount != "" {
+			r.mu.Lock()
+			_, exists := r.blobs[mount]
+			r.mu.Unlock()
+			if exists {
+				r.writeBlobCreated(w, name, mount)
+				return
+			}
+		}
+		if digest := query.Get("digest"); digest != "" {
+			r.putBlob(w, name, digest, body)
+			return
+		}
+		id, err := newUploadID()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+			return
+		}
+		r.mu.Lock()
+		r.uploads[id] = body
+		r.mu.Unlock()
+		writeUploadStatus(w, http.StatusAccepted, name, id, len(body))
+	case req.Method == http.MethodPatch || req.Method == http.MethodPut || req.Method == http.MethodGet:
+		r.mu.Lock()
+		upload, exists := r.uploads[id]
+		if exists {
+			upload = append(upload, body...)
+			r.uploads[id] = upload
+		}
+		if exists && req.Method == http.MethodPut {
+			delete(r.uploads, id)
+		}
+		r.mu.Unlock()
+		if !exists {
+			writeError(w, http.StatusNotFound, "BLOB_UPLOAD_UNKNOWN", fmt.Sprintf("upload %s is unknown", id))
+			return
+		}
+		switch req.Method {
+		case http.MethodPut:
+			r.putBlob(w, name, query.Get("digest"), upload)
+		case http.MethodPatch:
+			writeUploadStatus(w, http.StatusAccepted, name, id, len(upload))
+		default:
+			writeUploadStatus(w, http.StatusNoContent, name, id, len(upload))
+		}
+	case req.Method == http.MethodDelete:
+		r.mu.Lock()
+		delete(r.uploads, id)
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", req.Method)
+	}
+}
+
+func (r *Registry) putBlob(w http.ResponseWriter, name, digest string, content []byte) {
+	if err := verifyDigest(digest, content); err != nil {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return
+	}
+	r.mu.Lock()
+	r.blobs[digest] = content
+	r.mu.Unlock()
+	r.writeBlobCreated(w, name, digest)
+}
+
+func (r *Registry) writeBlobCreated(w http.ResponseWriter, name, digest string) {
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/%s", name, digest))
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func writeUploadStatus(w http.ResponseWriter, status int, name, id string, size int) {
+	w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%s", name, id))
+	w.Header().Set("Docker-Upload-UUID", id)
+	// The range is inclusive, and "0-0" is used for an empty upload by convention.
+	w.Header().Set("Range", fmt.Sprintf("0-%d", max(size-1, 0)))
+	w.Header().Set("Content-Length", "0")
+	w.WriteHeader(status)
+}
+
+func (r *Registry) serveManifest(w http.ResponseWriter, req *http.Request, name, ref string) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		r.mu.Lock()
+		m, exists := r.manifests[name][ref]
+		r.mu.Unlock()
+		if !exists {
+			writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", fmt.Sprintf("manifest %s is unknown to repository %s", ref, name))
+			return
+		}
+		w.Header().Set("Content-Type", m.mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(m.content)))
+		w.Header().Set("Docker-Content-Digest", m.digest)
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(m.content)
+		}
+	case http.MethodPut:
+		content, err := io.ReadAll(req.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+			return
+		}
+		m := manifest{
+			mediaType: manifestMediaType(req.Header.Get("Content-Type"), content),
+			digest:    sha256Digest(content),
+			content:   content,
+		}
+		if strings.Contains(ref, ":") && ref != m.digest {
+			writeError(w, http.StatusBadRequest, "DIGEST_INVALID", fmt.Sprintf("manifest digest is %s, not %s", m.digest, ref))
+			return
+		}
+		r.mu.Lock()
+		if r.manifests[name] == nil {
+			r.manifests[name] = map[string]manifest{}
+		}
+		r.manifests[name][ref] = m
+		r.manifests[name][m.digest] = m
+		r.mu.Unlock()
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/manifests/%s", name, m.digest))
+		w.Header().Set("Docker-Content-Digest", m.digest)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		r.mu.Lock()
+		delete(r.manifests[name], ref)
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", req.Method)
+	}
+}
+
+func (r *Registry) serveTags(w http.ResponseWriter, name string) {
+	r.mu.Lock()
+	tags := []string{}
+	for ref := range r.manifests[name] {
+		if !strings.Contains(ref, ":") {
+			tags = append(tags, ref)
+		}
+	}
+	r.mu.Unlock()
+	if len(tags) == 0 {
+		writeError(w, http.StatusNotFound, "NAME_UNKNOWN", fmt.Sprintf("repository %s is unknown", name))
+		return
+	}
+	sort.Strings(tags)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}{Name: name, Tags: tags})
+}
+
+// manifestMediaType returns the media type of a pushed manifest.
+// The Content-Type header takes precedence, and the mediaType field of the manifest is used as a fallback.
+func manifestMediaType(contentType string, content []byte) string {
+	if contentType != "" {
+		return contentType
+	}
+	var m struct {
+		MediaType string `json:"mediaType"`
+	}
+	if json.Unmarshal(content, &m) == nil && m.MediaType != "" {
+		return m.MediaType
+	}
+	return defaultManifestMediaType
+}
+
+func sha256Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func verifyDigest(digest string, content []byte) error {
+	algorithm, encoded, _ := strings.Cut(digest, ":")
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported digest %q", digest)
+	}
+	h.Write(content)
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != encoded {
+		return fmt.Errorf("content digest is %s:%s, not %s", algorithm, actual, digest)
+	}
+	return nil
+}
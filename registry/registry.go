@@ -0,0 +1,193 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package registry implements an in-process container registry
+// that can stand in for a registry container during testing.
+//
+// It implements the subset of the OCI distribution API [1] that is needed to push and pull images,
+// stores everything in memory, and optionally authenticates the clients with an htpasswd file.
+//
+// Since the registry is served from the test binary, the test subject must be able to reach localhost of the host
+// that runs the tests (e.g., nerdctl running natively on Linux).
+//
+// [1] https://github.com/opencontainers/distribution-spec/blob/main/spec.md
+package registry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const realm = "Registry Realm"
+
+// Modifier modifies a Registry before it is served.
+type Modifier func(*Registry) error
+
+// WithHtpasswd requires the clients to authenticate with one of the credentials in htpasswd.
+//
+// htpasswd has the same format as the file generated by `htpasswd -B`, and only bcrypt hashes are supported.
+func WithHtpasswd(htpasswd string) Modifier {
+	return func(r *Registry) error {
+		for _, line := range strings.Split(htpasswd, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			user, hash, found := strings.Cut(line, ":")
+			if !found || !strings.HasPrefix(hash, "$2") {
+				return fmt.Errorf("unsupported htpasswd entry for user %q", user)
+			}
+			r.credentials[user] = []byte(hash)
+		}
+		return nil
+	}
+}
+
+// Registry is an in-memory container registry. It's safe for concurrent use.
+type Registry struct {
+	credentials map[string][]byte
+
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string]map[string]manifest
+	uploads   map[string][]byte
+
+	server *http.Server
+	addr   string
+}
+
+type manifest struct {
+	mediaType string
+	digest    string
+	content   []byte
+}
+
+// New creates a Registry. Use it as an http.Handler directly or invoke Start to serve it.
+func New(modifiers ...Modifier) (*Registry, error) {
+	r := &Registry{
+		credentials: map[string][]byte{},
+		blobs:       map[string][]byte{},
+		manifests:   map[string]map[string]manifest{},
+		uploads:     map[string][]byte{},
+	}
+	for _, modify := range modifiers {
+		if err := modify(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Start creates a Registry and serves it on localhost:port in the background.
+// It's the caller's responsibility to invoke Close when the registry is no longer needed.
+func Start(port int, modifiers ...Modifier) *Registry {
+	r, err := New(modifiers...)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+	r.addr = fmt.Sprintf("localhost:%d", port)
+	l, err := net.Listen("tcp", r.addr)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	r.server = &http.Server{Handler: r, ReadHeaderTimeout: 30 * time.Second}
+	go func() {
+		if err := r.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ginkgo.GinkgoWriter.Printf("registry on %s stopped unexpectedly: %v\n", r.addr, err)
+		}
+	}()
+	return r
+}
+
+// Addr returns the address that the registry is served on (e.g., localhost:5000),
+// which can be used as the registry part of an image reference.
+func (r *Registry) Addr() string {
+	return r.addr
+}
+
+// Close stops serving the registry.
+func (r *Registry) Close() {
+	if r.server != nil {
+		gomega.Expect(r.server.Close()).Should(gomega.Succeed())
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if !r.authenticated(req) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", realm))
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if path == req.URL.Path {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "not found")
+		return
+	}
+	if path == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if name, id, found := cutLast(path, "/blobs/uploads"); found {
+		r.serveUpload(w, req, name, strings.TrimPrefix(id, "/"))
+		return
+	}
+	if name, digest, found := cutLast(path, "/blobs/"); found {
+		r.serveBlob(w, req, name, digest)
+		return
+	}
+	if name, ref, found := cutLast(path, "/manifests/"); found {
+		r.serveManifest(w, req, name, ref)
+		return
+	}
+	if name, rest, found := cutLast(path, "/tags/list"); found && rest == "" {
+		r.serveTags(w, name)
+		return
+	}
+	writeError(w, http.StatusNotFound, "NOT_FOUND", "not found")
+}
+
+func (r *Registry) authenticated(req *http.Request) bool {
+	if len(r.credentials) == 0 {
+		return true
+	}
+	user, password, ok := req.BasicAuth()
+	if !ok {
+		return false
+	}
+	hash, exists := r.credentials[user]
+	return exists && bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i <= 0 {
+		return "", "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+func newUploadID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"errors":[{"code":%q,"message":%q}]}`, code, message)
+}
@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+//nolint:gosec // This password is only used for testing purpose.
+const htpasswd = "testUser:$2y$05$wE0sj3r9O9K9q7R0MXcfPuIerl/06L1IsxXkCuUr3QZ8lHWwicIdS"
+
+func newServer(t *testing.T, modifiers ...Modifier) *httptest.Server {
+	t.Helper()
+	r, err := New(modifiers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := httptest.NewServer(r)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func do(t *testing.T, method, url string, body []byte, header http.Header) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func expectStatus(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+	if resp.StatusCode != want {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected status %d for %s %s, got %d: %s", want, resp.Request.Method, resp.Request.URL, resp.StatusCode, body)
+	}
+}
+
+func TestPushAndPull(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	blob := []byte("layer content")
+	digest := sha256Digest(blob)
+
+	expectStatus(t, do(t, http.MethodGet, s.URL+"/v2/", nil, nil), http.StatusOK)
+	expectStatus(t, do(t, http.MethodHead, s.URL+"/v2/library/alpine/blobs/"+digest, nil, nil), http.StatusNotFound)
+
+	// Chunked upload: POST, PATCH, and then PUT with the digest.
+	resp := do(t, http.MethodPost, s.URL+"/v2/library/alpine/blobs/uploads/", nil, nil)
+	expectStatus(t, resp, http.StatusAccepted)
+	location := s.URL + resp.Header.Get("Location")
+	expectStatus(t, do(t, http.MethodPatch, location, blob[:5], nil), http.StatusAccepted)
+	expectStatus(t, do(t, http.MethodPut, location+"?digest="+digest, blob[5:], nil), http.StatusCreated)
+
+	resp = do(t, http.MethodGet, s.URL+"/v2/library/alpine/blobs/"+digest, nil, nil)
+	expectStatus(t, resp, http.StatusOK)
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, blob) {
+		t.Fatalf("expected blob %q, got %q", blob, got)
+	}
+
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json"}`)
+	resp = do(t, http.MethodPut, s.URL+"/v2/library/alpine/manifests/latest", manifest,
+		http.Header{"Content-Type": {"application/vnd.oci.image.manifest.v1+json"}})
+	expectStatus(t, resp, http.StatusCreated)
+	if resp.Header.Get("Docker-Content-Digest") != sha256Digest(manifest) {
+		t.Fatalf("unexpected manifest digest %s", resp.Header.Get("Docker-Content-Digest"))
+	}
+
+	for _, ref := range []string{"latest", sha256Digest(manifest)} {
+		resp = do(t, http.MethodGet, s.URL+"/v2/library/alpine/manifests/"+ref, nil, nil)
+		expectStatus(t, resp, http.StatusOK)
+		if resp.Header.Get("Content-Type") != "application/vnd.oci.image.manifest.v1+json" {
+			t.Fatalf("unexpected manifest media type %s", resp.Header.Get("Content-Type"))
+		}
+	}
+	expectStatus(t, do(t, http.MethodGet, s.URL+"/v2/library/alpine/tags/list", nil, nil), http.StatusOK)
+}
+
+func TestMonolithicUploadRejectsWrongDigest(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	resp := do(t, http.MethodPost, s.URL+"/v2/foo/blobs/uploads/?digest="+sha256Digest([]byte("foo")), []byte("bar"), nil)
+	expectStatus(t, resp, http.StatusBadRequest)
+}
+
+func TestHtpasswd(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t, WithHtpasswd(htpasswd))
+
+	resp := do(t, http.MethodGet, s.URL+"/v2/", nil, nil)
+	expectStatus(t, resp, http.StatusUnauthorized)
+	if resp.Header.Get("WWW-Authenticate") == "" {
+		t.Fatal("expected a WWW-Authenticate challenge")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/v2/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for password, want := range map[string]int{"testPassword": http.StatusOK, "invalidPassword": http.StatusUnauthorized} {
+		req.SetBasicAuth("testUser", password)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("expected status %d with password %s, got %d", want, password, resp.StatusCode)
+		}
+	}
+}
+
+func TestWithHtpasswdRejectsUnsupportedHash(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(WithHtpasswd("testUser:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=")); err == nil {
+		t.Fatal("expected New to fail")
+	}
+}
@@ -5,91 +5,179 @@ package run
 
 import (
 	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
 
 	"github.com/runfinch/common-tests/option"
 	"github.com/runfinch/common-tests/tests"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // From https://pkg.go.dev/testing#hdr-Main:
 // "Command line flags are always parsed by the time test or benchmark functions run."
 // As a result, we need to define the custom flags below as global variables so that
 // when `flag.Parse` is invoked by the `testing` package, they can also be parsed.
-var subject = flag.String("subject", "", "the subject to be tested, potentially containing spaces")
+var (
+	subject         = flag.String("subject", "", "the subject to be tested, potentially containing spaces")
+	imageArchiveDir = flag.String("image-archive-dir", "",
+		"the directory containing the image archives to seed the local registry with instead of pulling the images")
+	imageCatalog      = flag.String("image-catalog", "", "the YAML or JSON file overriding the images used by the tests")
+	inProcessRegistry = flag.Bool("in-process-registry", false,
+		"serve the registries needed by the tests from the test binary instead of running them as containers")
+	labelScopedCleanup = flag.Bool("label-scoped-cleanup", false,
+		"only remove the containers, images, volumes and networks created by the tests when cleaning up")
+	transcriptPath = flag.String("transcript", "", "the JSONL file to record every command run by the tests to")
+	replay         = flag.String("replay", "",
+		"the transcript recorded with -transcript to replay instead of running the subject, which is ignored then")
+	sshDestination = flag.String("ssh-destination", "",
+		"the host (e.g., user@host) to run the subject on over SSH instead of running it locally")
+	sshArgs       = flag.String("ssh-args", "", "the additional arguments passed to ssh, potentially containing spaces")
+	subjectPrefix = flag.String("subject-prefix", "",
+		"the command (e.g., docker exec -i dev-box) that the subject is run with, potentially containing spaces")
+	subjectTerminalPrefix = flag.String("subject-terminal-prefix", "",
+		"the command (e.g., docker exec -it dev-box) that the subject is run with in a terminal, the specs needing one are skipped if it's empty")
+	pathMappings = flag.String("path-mappings", "",
+		"the host directories and where they are mounted where the subject runs, e.g., /home/user=/host-home,/tmp=/host-tmp")
+	hostGatewayIP = flag.String("host-gateway-ip", "192.168.5.2",
+		"the IP that the subject resolves host-gateway to (the default is the one of the Finch VM), which is detected if it's empty")
+	componentVersions = flag.String("component-versions", "",
+		"the versions of the components that cannot be detected from the subject, e.g., cni=1.6.0,compose=2.0.2")
+	knownIssues = flag.String("known-issues", "",
+		"the YAML or JSON file listing the known issues of the subject whose specs are expected to fail")
+	leakCheck = flag.String("leak-check", "",
+		"what to do when a spec leaves containers, images, volumes or networks behind: off (default), report or fail")
+)
 
 //nolint:paralleltest // TestRun is like TestMain for the e2e tests.
 func TestRun(t *testing.T) {
-	o, err := option.New(strings.Split(*subject, " "))
+	modifiers := modifiersFromFlags(t)
+	subjectArgs := strings.Split(*subject, " ")
+	if *replay != "" {
+		subjectArgs = replaySubject(t, *replay)
+	}
+	o, err := option.New(subjectArgs, modifiers...)
 	if err != nil {
 		t.Fatalf("failed to initialize a testing option: %v", err)
 	}
+	leakCheckMode, err := testutil.ParseLeakCheckMode(*leakCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
 
+	runOption := &tests.RunOption{BaseOpt: o, DefaultHostGatewayIP: *hostGatewayIP}
 	ginkgo.SynchronizedBeforeSuite(func() []byte {
 		tests.SetupLocalRegistry(o)
-		return nil
-	}, func(_ []byte) {})
+		return tests.LocalRegistryState()
+	}, func(state []byte) {
+		tests.UseLocalRegistry(o, state)
+		runOption.CGMode = tests.DetectCGMode(o)
+	})
 
-	ginkgo.SynchronizedAfterSuite(func() {
+	// The local registry is cleaned up by the process that set it up after the other ones are done.
+	ginkgo.SynchronizedAfterSuite(func() {}, func() {
 		tests.CleanupLocalRegistry(o)
-	}, func() {})
+	})
 
 	const description = "Finch Shared E2E Tests"
-	const defaultHostGatewayIP = "192.168.5.2"
 	ginkgo.Describe(description, func() {
-		// Every test should be listed here.
-		// TODO: add tests for "system prune" and "network prune" after upgrading nerdctl to v0.23
-		tests.Pull(o)
-		tests.Rm(o)
-		tests.Rmi(o)
-		tests.Run(&tests.RunOption{BaseOpt: o, CGMode: tests.Unified, DefaultHostGatewayIP: defaultHostGatewayIP})
-		tests.Start(o)
-		tests.Stop(o)
-		tests.Cp(o)
-		tests.Tag(o)
-		tests.Save(o)
-		tests.Load(o)
-		tests.Build(o)
-		tests.Push(o)
-		tests.Images(o)
-		tests.ComposeBuild(o)
-		tests.ComposeDown(o)
-		tests.ComposeKill(o)
-		tests.ComposePs(o)
-		tests.ComposePull(o)
-		tests.ComposeLogs(o)
-		tests.Create(o)
-		tests.Port(o)
-		tests.Kill(o)
-		tests.Restart(o)
-		tests.Stats(o)
-		tests.BuilderPrune(o)
-		tests.Exec(o)
-		tests.Logs(o)
-		tests.Login(o)
-		tests.Logout(o)
-		tests.VolumeCreate(o)
-		tests.VolumeInspect(o)
-		tests.VolumeLs(o)
-		tests.VolumeRm(o)
-		tests.VolumePrune(o)
-		tests.ImageHistory(o)
-		tests.ImageInspect(o)
-		tests.ImagePrune(o)
-		tests.Info(o)
-		tests.Events(o)
-		tests.Inspect(o)
-		tests.NetworkCreate(o)
-		tests.NetworkInspect(o)
-		tests.NetworkLs(o)
-		tests.NetworkRm(o)
-		tests.Ps(o)
-		tests.HealthCheck(o)
+		tests.All(o, tests.WithoutSuites("Run"))
+		tests.Run(runOption)
 	})
 
-	gomega.RegisterFailHandler(ginkgo.Fail)
+	testutil.CheckLeaks(o, leakCheckMode)
+	testutil.GateComponentVersions(o)
+
+	failHandler := ginkgo.Fail
+	if *knownIssues != "" {
+		k, err := testutil.LoadKnownIssues(*knownIssues)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k.Register()
+		failHandler = k.FailHandler(ginkgo.Fail)
+	}
+
+	gomega.RegisterFailHandler(failHandler)
 	ginkgo.RunSpecs(t, description)
 }
+
+// replaySubject builds the fake subject and returns a subject that replays the transcript with it.
+func replaySubject(t *testing.T, transcript string) []string {
+	bin, err := gexec.Build("github.com/runfinch/common-tests/fake/cmd/fake-subject")
+	if err != nil {
+		t.Fatalf("failed to build the fake subject: %v", err)
+	}
+	t.Cleanup(gexec.CleanupBuildArtifacts)
+	dir := t.TempDir()
+	scenario := fmt.Sprintf("log: %s\nreplay: %s\n", filepath.Join(dir, "invocations.jsonl"), transcript)
+	scenarioPath := filepath.Join(dir, "scenario.yaml")
+	if err := os.WriteFile(scenarioPath, []byte(scenario), 0o600); err != nil {
+		t.Fatalf("failed to write the replay scenario: %v", err)
+	}
+	return []string{bin, scenarioPath}
+}
+
+// modifiersFromFlags returns the option modifiers specified by the flags.
+func modifiersFromFlags(t *testing.T) []option.Modifier {
+	var modifiers []option.Modifier
+	if *imageArchiveDir != "" {
+		modifiers = append(modifiers, option.WithImageArchiveDir(*imageArchiveDir))
+	}
+	if *imageCatalog != "" {
+		c, err := option.LoadImageCatalog(*imageCatalog)
+		if err != nil {
+			t.Fatalf("failed to load the image catalog: %v", err)
+		}
+		modifiers = append(modifiers, option.WithImageCatalog(c))
+	}
+	if *inProcessRegistry {
+		modifiers = append(modifiers, option.WithInProcessRegistry())
+	}
+	if *transcriptPath != "" {
+		modifiers = append(modifiers, option.WithTranscript(*transcriptPath))
+	}
+	if *sshDestination != "" {
+		modifiers = append(modifiers, option.WithTransport(option.SSHTransport{
+			Destination: *sshDestination,
+			Args:        strings.Fields(*sshArgs),
+		}))
+	}
+	mappings, err := option.ParsePathMappings(*pathMappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mappings) > 0 {
+		modifiers = append(modifiers, option.WithPathMapper(option.MountPrefixes(mappings)))
+	}
+	if *subjectPrefix != "" {
+		if *sshDestination != "" {
+			t.Fatal("-subject-prefix and -ssh-destination cannot be used together")
+		}
+		modifiers = append(modifiers, option.WithTransport(option.PrefixTransport{
+			Prefix:         strings.Fields(*subjectPrefix),
+			TerminalPrefix: strings.Fields(*subjectTerminalPrefix),
+			PathMappings:   mappings,
+		}))
+	}
+	for _, cv := range strings.Split(*componentVersions, ",") {
+		if cv == "" {
+			continue
+		}
+		component, version, ok := strings.Cut(cv, "=")
+		if !ok {
+			t.Fatalf("invalid component version %q, expected <component>=<version>", cv)
+		}
+		modifiers = append(modifiers, option.WithComponentVersion(component, version))
+	}
+	if *labelScopedCleanup {
+		modifiers = append(modifiers, option.WithLabelScopedCleanup())
+	}
+	return modifiers
+}
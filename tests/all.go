@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"slices"
+
+	"github.com/runfinch/common-tests/option"
+)
+
+// Suite is a test suite in this package, e.g., the one registered by Pull.
+type Suite struct {
+	// Name is the name of the function that registers the test suite, e.g., "Pull".
+	Name string
+	// Labels are the labels that all the specs of the test suite are decorated with, e.g., LabelImage.
+	// Some specs have more labels (e.g., LabelNeedsPrivilege), which the filters of All don't see;
+	// use --ginkgo.label-filter to select them.
+	Labels []string
+
+	register func(o *option.Option)
+}
+
+// Filter selects the test suites registered by All.
+type Filter func(Suite) bool
+
+// WithLabels selects the test suites that have any of the labels.
+func WithLabels(labels ...string) Filter {
+	return func(s Suite) bool {
+		return slices.ContainsFunc(s.Labels, func(l string) bool { return slices.Contains(labels, l) })
+	}
+}
+
+// WithoutLabels selects the test suites that have none of the labels, e.g., WithoutLabels(LabelSlow).
+func WithoutLabels(labels ...string) Filter {
+	return func(s Suite) bool {
+		return !WithLabels(labels...)(s)
+	}
+}
+
+// WithoutSuites selects the test suites other than the ones with the names,
+// e.g., WithoutSuites("Run") to register Run with a custom RunOption.
+func WithoutSuites(names ...string) Filter {
+	return func(s Suite) bool {
+		return !slices.Contains(names, s.Name)
+	}
+}
+
+// Suites returns all the test suites in this package in the order that All registers them.
+func Suites() []Suite {
+	registers := []struct {
+		name     string
+		register func(o *option.Option)
+	}{
+		{"Pull", Pull},
+		{"Rm", Rm},
+		{"Rmi", Rmi},
+		{"Run", func(o *option.Option) { Run(&RunOption{BaseOpt: o, detectCGMode: true}) }},
+		{"Start", Start},
+		{"Stop", Stop},
+		{"Cp", Cp},
+		{"Tag", Tag},
+		{"Save", Save},
+		{"Load", Load},
+		{"Build", Build},
+		{"Push", Push},
+		{"Images", Images},
+		{"ComposeBuild", ComposeBuild},
+		{"ComposeDown", ComposeDown},
+		{"ComposeKill", ComposeKill},
+		{"ComposePs", ComposePs},
+		{"ComposePull", ComposePull},
+		{"ComposeLogs", ComposeLogs},
+		{"Create", Create},
+		{"Port", Port},
+		{"Kill", Kill},
+		{"Restart", Restart},
+		{"Stats", Stats},
+		{"BuilderPrune", BuilderPrune},
+		{"Exec", Exec},
+		{"Attach", Attach},
+		{"Logs", Logs},
+		{"Login", Login},
+		{"Logout", Logout},
+		{"VolumeCreate", VolumeCreate},
+		{"VolumeInspect", VolumeInspect},
+		{"VolumeLs", VolumeLs},
+		{"VolumeRm", VolumeRm},
+		{"VolumePrune", VolumePrune},
+		{"ImageHistory", ImageHistory},
+		{"ImageInspect", ImageInspect},
+		{"ImagePrune", ImagePrune},
+		{"Info", Info},
+		{"Events", Events},
+		{"Inspect", Inspect},
+		{"NetworkCreate", NetworkCreate},
+		{"NetworkInspect", NetworkInspect},
+		{"NetworkLs", NetworkLs},
+		{"NetworkRm", NetworkRm},
+		{"Ps", Ps},
+		{"HealthCheck", HealthCheck},
+		{"SystemPrune", SystemPrune},
+		{"NetworkPrune", NetworkPrune},
+		{"Pause", Pause},
+		{"Unpause", Unpause},
+		{"Wait", Wait},
+		{"Rename", Rename},
+		{"Top", Top},
+		{"Update", func(o *option.Option) { Update(&UpdateOption{BaseOpt: o, detectCGMode: true}) }},
+		{"Commit", Commit},
+		{"Export", Export},
+		{"Import", Import},
+	}
+
+	suites := make([]Suite, 0, len(registers))
+	for _, r := range registers {
+		suites = append(suites, Suite{Name: r.name, Labels: slices.Clone(suiteLabels[r.name]), register: r.register})
+	}
+	return suites
+}
+
+// All registers the test suites in this package that are selected by all the filters, or all of them if there is no filter.
+// It's meant to be called in a ginkgo.Describe, e.g.,
+//
+//	ginkgo.Describe("Finch Shared E2E Tests", func() {
+//		tests.All(o, tests.WithoutLabels(tests.LabelCompose))
+//	})
+//
+// Run and Update are registered with options whose cgroup mode and host gateway IP are detected when the specs run
+// (see DetectCGMode and DetectHostGatewayIP).
+func All(o *option.Option, filters ...Filter) {
+	for _, s := range Suites() {
+		if selected(s, filters) {
+			s.register(o)
+		}
+	}
+}
+
+func selected(s Suite, filters []Filter) bool {
+	return !slices.ContainsFunc(filters, func(f Filter) bool { return !f(s) })
+}
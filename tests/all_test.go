@@ -0,0 +1,76 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuitesHaveLabels(t *testing.T) {
+	t.Parallel()
+
+	suites := Suites()
+	if len(suites) != len(suiteLabels) {
+		t.Fatalf("expected %d suites like the labels, got %d", len(suiteLabels), len(suites))
+	}
+	for _, s := range suites {
+		if len(s.Labels) == 0 {
+			t.Fatalf("expected suite %s to have labels", s.Name)
+		}
+	}
+}
+
+func TestLabelsOfUnknownSuite(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected labelsOf to panic for an unknown suite")
+		}
+	}()
+	labelsOf("Pul")
+}
+
+func TestFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filters []Filter
+		want    []string
+	}{
+		{
+			name:    "WithLabels",
+			filters: []Filter{WithLabels(LabelNetwork)},
+			want:    []string{"NetworkCreate", "NetworkInspect", "NetworkLs", "NetworkRm", "SystemPrune", "NetworkPrune"},
+		},
+		{
+			name:    "WithoutLabels",
+			filters: []Filter{WithLabels(LabelCompose), WithoutLabels(LabelSlow)},
+			want:    []string{"ComposeDown", "ComposeKill", "ComposePs", "ComposePull", "ComposeLogs"},
+		},
+		{
+			name:    "WithoutSuites",
+			filters: []Filter{WithLabels(LabelRegistry), WithoutSuites("Push")},
+			want:    []string{"Login", "Logout"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, s := range Suites() {
+				if selected(s, test.filters) {
+					got = append(got, s.Name)
+				}
+			}
+			if !slices.Equal(got, test.want) {
+				t.Fatalf("expected suites %v, got %v", test.want, got)
+			}
+		})
+	}
+}
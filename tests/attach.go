@@ -0,0 +1,86 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// terminalTimeout is how long the tests wait for a command running in a pseudo-terminal to respond or to exit.
+const terminalTimeout = 30 * time.Second
+
+// customDetachKeys is the detach key sequence used by the tests to check --detach-keys.
+const customDetachKeys = "ctrl-a,ctrl-b"
+
+// Attach tests the "attach" command that attaches the terminal to a running container.
+func Attach(o *option.Option) {
+	ginkgo.Describe("attach to a container", labelsOf("Attach"), func() {
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o)
+		})
+
+		ginkgo.When("the container is running with a TTY", func() {
+			ginkgo.BeforeEach(func() {
+				command.Run(o, "run", "-dit", "--name", testContainerName(), localImages[defaultImage], "sh")
+			})
+
+			ginkgo.It("should send the input to the container and receive its output", func() {
+				terminal := command.StartInTerminal(o, "attach", testContainerName())
+				shouldRespondInTerminal(terminal)
+				shouldDetachInTerminal(o, terminal, command.DefaultDetachKeys)
+			})
+
+			ginkgo.It("should exit with the container when its main process exits", func() {
+				terminal := command.StartInTerminal(o, "attach", testContainerName())
+				shouldRespondInTerminal(terminal)
+				terminal.SendLine("exit 3")
+				terminal.Wait(terminalTimeout)
+				state := inspect.Container(o, testContainerName()).State
+				gomega.Expect(state.Status).To(gomega.Equal("exited"))
+				gomega.Expect(state.ExitCode).To(gomega.Equal(3))
+			})
+
+			ginkgo.It("should detach from the container with the keys specified by --detach-keys flag", func() {
+				terminal := command.StartInTerminal(o, "attach", "--detach-keys", customDetachKeys, testContainerName())
+				shouldRespondInTerminal(terminal)
+				terminal.SendDetachKeys(command.DefaultDetachKeys)
+				gomega.Consistently(terminal.Exited).WithTimeout(time.Second).ShouldNot(gomega.BeClosed())
+				shouldDetachInTerminal(o, terminal, customDetachKeys)
+			})
+		})
+
+		ginkgo.It("should not attach to a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "attach", nonexistentContainerName)
+		})
+
+		ginkgo.It("should not attach to a stopped container", func() {
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			command.RunWithoutSuccessfulExit(o, "attach", testContainerName())
+		})
+	})
+}
+
+// shouldRespondInTerminal checks that the shell running in the terminal is interactive, and that its stdout is a TTY.
+func shouldRespondInTerminal(terminal *command.Terminal) {
+	// The arithmetic expansion tells the output of the command from the echo of the keystrokes.
+	terminal.SendLine(`[ -t 1 ] && echo "tty-$((20 + 22))"`)
+	gomega.Eventually(terminal).WithTimeout(terminalTimeout).Should(gbytes.Say("tty-42"))
+}
+
+// shouldDetachInTerminal detaches from the container with the keys, and checks that the container is still running.
+func shouldDetachInTerminal(o *option.Option, terminal *command.Terminal, keys string) {
+	terminal.SendDetachKeys(keys)
+	gomega.Eventually(terminal).WithTimeout(terminalTimeout).Should(gexec.Exit())
+	containerShouldBeRunning(o, testContainerName())
+}
@@ -24,7 +24,7 @@ import (
 // --no-cache flag is added to tests asserting the output from `RUN` command.
 // [Discussion]: https://github.com/runfinch/common-tests/pull/4#discussion_r971338825
 func Build(o *option.Option) {
-	ginkgo.Describe("Build container image", func() {
+	ginkgo.Describe("Build container image", labelsOf("Build"), ginkgo.Serial, func() {
 		ginkgo.Context("Build container image using default image", func() {
 			var buildContext string
 			ginkgo.BeforeEach(func() {
@@ -41,8 +41,8 @@ func Build(o *option.Option) {
 
 			for _, tag := range []string{"-t", "--tag"} {
 				ginkgo.It(fmt.Sprintf("build basic alpine image with %s option", tag), func() {
-					command.Run(o, "build", tag, testImageName, buildContext)
-					imageShouldExist(o, testImageName)
+					command.Run(o, "build", tag, testImageName(), buildContext)
+					imageShouldExist(o, testImageName())
 				})
 			}
 
@@ -125,7 +125,7 @@ func Build(o *option.Option) {
 				gomega.Expect(ffs.CheckIfFileExists(outputFilePath)).To(gomega.Equal(true))
 			})
 
-			ginkgo.It("Build an image with --ssh option", func() {
+			ginkgo.It("Build an image with --ssh option", ginkgo.Label(LabelNotWindows), func() {
 				if runtime.GOOS == "windows" {
 					ginkgo.Skip("non-functional on Windows, see https://github.com/runfinch/finch/issues/750")
 				}
@@ -198,8 +198,8 @@ func Build(o *option.Option) {
 			// we need to pull alpineImage instead of localImages[defaultImage]
 			// because we can be sure that the registry associated with the former provides the image with the platform specified below.
 			ginkgo.It("build basic alpine image with --platform option", func() {
-				command.Run(o, "build", "-t", testImageName, "--platform=amd64", buildContext)
-				platform := command.StdoutStr(o, "images", testImageName, "--format", "{{.Platform}}")
+				command.Run(o, "build", "-t", testImageName(), "--platform=amd64", buildContext)
+				platform := command.StdoutStr(o, "images", testImageName(), "--format", "{{.Platform}}")
 				gomega.Expect(platform).Should(gomega.Equal("linux/amd64"))
 			})
 		})
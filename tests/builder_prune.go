@@ -16,7 +16,7 @@ import (
 
 // BuilderPrune tests the "builder prune" command that prunes the builder cache.
 func BuilderPrune(o *option.Option) {
-	ginkgo.Describe("prune the builder cache", func() {
+	ginkgo.Describe("prune the builder cache", labelsOf("BuilderPrune"), ginkgo.Serial, func() {
 		var buildContext string
 		ginkgo.BeforeEach(func() {
 			buildContext = ffs.CreateBuildContext(fmt.Sprintf(`FROM %s
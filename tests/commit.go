@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+)
+
+// Commit tests the "commit" command that creates a new image from the changes of a container.
+func Commit(o *option.Option) {
+	ginkgo.Describe("commit a container", labelsOf("Commit"), ginkgo.Serial, func() {
+		ginkgo.BeforeEach(func() {
+			command.RemoveAll(o)
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "exec", testContainerName(), "sh", "-c", "echo committed > /committed.txt")
+		})
+		ginkgo.AfterEach(func() {
+			command.RemoveAll(o)
+		})
+
+		ginkgo.It("should commit the changes to the filesystem of a running container", func() {
+			command.Run(o, "commit", testContainerName(), testImageName())
+			imageShouldExist(o, testImageName())
+			gomega.Expect(command.StdoutStr(o, "run", "--rm", testImageName(), "cat", "/committed.txt")).To(gomega.Equal("committed"))
+			containerShouldBeRunning(o, testContainerName())
+		})
+
+		for _, flags := range [][]string{{"-m", "-a"}, {"--message", "--author"}} {
+			ginkgo.It(fmt.Sprintf("should set the commit message and the author with %s and %s flags", flags[0], flags[1]), func() {
+				const message, author = "test commit message", "Test Author <test@example.com>"
+				command.Run(o, "commit", flags[0], message, flags[1], author, testContainerName(), testImageName())
+				image := inspect.Image(o, testImageName())
+				gomega.Expect(image.Comment).To(gomega.Equal(message))
+				gomega.Expect(image.Author).To(gomega.Equal(author))
+			})
+		}
+
+		for _, change := range []string{"-c", "--change"} {
+			ginkgo.It(fmt.Sprintf("should apply the Dockerfile instructions to the image config with %s flag", change), func() {
+				command.Run(o, "commit", change, "ENV FOO=bar", change, `CMD ["cat", "/committed.txt"]`, change, "WORKDIR /tmp",
+					testContainerName(), testImageName())
+				config := inspect.Image(o, testImageName()).Config
+				gomega.Expect(config.Env).To(gomega.ContainElement("FOO=bar"))
+				gomega.Expect(config.Cmd).To(gomega.Equal([]string{"cat", "/committed.txt"}))
+				gomega.Expect(config.WorkingDir).To(gomega.Equal("/tmp"))
+				gomega.Expect(command.StdoutStr(o, "run", "--rm", testImageName())).To(gomega.Equal("committed"))
+				gomega.Expect(command.StdoutStr(o, "run", "--rm", testImageName(), "sh", "-c", "echo $FOO")).To(gomega.Equal("bar"))
+			})
+		}
+
+		ginkgo.It("should not commit a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "commit", nonexistentContainerName, testImageName())
+			imageShouldNotExist(o, testImageName())
+		})
+
+		ginkgo.It("should not commit with an invalid Dockerfile instruction", func() {
+			command.RunWithoutSuccessfulExit(o, "commit", "--change", "INVALID instruction", testContainerName(), testImageName())
+			imageShouldNotExist(o, testImageName())
+		})
+	})
+}
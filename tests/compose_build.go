@@ -20,7 +20,7 @@ import (
 func ComposeBuild(o *option.Option) {
 	services := []string{"svc1_build_cmd", "svc2_build_cmd"}
 	imageSuffix := []string{"alpine:latest", "-svc2_build_cmd:latest"}
-	ginkgo.Describe("Compose build command", func() {
+	ginkgo.Describe("Compose build command", labelsOf("ComposeBuild"), ginkgo.Serial, func() {
 		var composeContext string
 		var composeFilePath string
 		ginkgo.BeforeEach(func() {
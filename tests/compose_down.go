@@ -22,7 +22,7 @@ func ComposeDown(o *option.Option) {
 	services := []string{"svc1_compose_down", "svc2_compose_down"}
 	containerNames := []string{"container1_compose_down", "container2_compose_down"}
 
-	ginkgo.Describe("Compose down command", func() {
+	ginkgo.Describe("Compose down command", labelsOf("ComposeDown"), ginkgo.Serial, func() {
 		var composeContext string
 		var composeFilePath string
 		ginkgo.BeforeEach(func() {
@@ -20,7 +20,7 @@ func ComposeKill(o *option.Option) {
 	services := []string{"svc1_compose_kill", "svc2_compose_kill"}
 	containerNames := []string{"container1_compose_kill", "container2_compose_kill"}
 
-	ginkgo.Describe("Compose kill command", func() {
+	ginkgo.Describe("Compose kill command", labelsOf("ComposeKill"), ginkgo.Serial, func() {
 		var composeContext string
 		var composeFilePath string
 		ginkgo.BeforeEach(func() {
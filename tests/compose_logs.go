@@ -20,7 +20,7 @@ func ComposeLogs(o *option.Option) {
 	services := []string{"svc1_compose_logs", "svc2_compose_logs"}
 	containerNames := []string{"container1_compose_logs", "container2_compose_logs"}
 
-	ginkgo.Describe("Compose logs command", func() {
+	ginkgo.Describe("Compose logs command", labelsOf("ComposeLogs"), ginkgo.Serial, func() {
 		var buildContext string
 		var composeFilePath string
 		var imageNames []string
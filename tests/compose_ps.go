@@ -20,7 +20,7 @@ func ComposePs(o *option.Option) {
 	services := []string{"svc1_compose_ps", "svc2_compose_ps"}
 	containerNames := []string{"container1_compose_ps", "container2_compose_ps"}
 
-	ginkgo.Describe("Compose ps command", func() {
+	ginkgo.Describe("Compose ps command", labelsOf("ComposePs"), ginkgo.Serial, func() {
 		var composeContext string
 		var composeFilePath string
 		var imageNames []string
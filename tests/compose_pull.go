@@ -18,7 +18,7 @@ import (
 // ComposePull tests functionality of `compose pull` command.
 func ComposePull(o *option.Option) {
 	services := []string{"svc1_compose_pull", "svc2_compose_pull"}
-	ginkgo.Describe("Compose pull command", func() {
+	ginkgo.Describe("Compose pull command", labelsOf("ComposePull"), ginkgo.Serial, func() {
 		var composeContext string
 		var composeFilePath string
 		var imageNames []string
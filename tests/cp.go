@@ -14,6 +14,7 @@ import (
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/ffs"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Cp tests `finch cp` command to copy files between container and host filesystems.
@@ -21,38 +22,33 @@ func Cp(o *option.Option) {
 	filename := "test-file"
 	content := "test-content"
 	containerFilepath := filepath.ToSlash(filepath.Join("/tmp", filename))
-	containerResource := fmt.Sprintf("%s:%s", testContainerName, containerFilepath)
+	containerResource := func() string { return fmt.Sprintf("%s:%s", testContainerName(), containerFilepath) }
 
-	ginkgo.Describe("copy from container to host and vice versa", func() {
+	ginkgo.Describe("copy from container to host and vice versa", labelsOf("Cp"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.When("the container is running", func() {
 			ginkgo.BeforeEach(func() {
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 			})
 
 			ginkgo.It("should be able to copy file from host to container", func() {
 				path := ffs.CreateTempFile(filename, content)
 				ginkgo.DeferCleanup(os.RemoveAll, filepath.Dir(path))
 
-				command.Run(o, "cp", path, containerResource)
-				fileShouldExistInContainer(o, testContainerName, containerFilepath, content)
+				command.Run(o, "cp", path, containerResource())
+				fileShouldExistInContainer(o, testContainerName(), containerFilepath, content)
 			})
 
 			ginkgo.It("should be able to copy file from container to host", func() {
 				cmd := fmt.Sprintf("echo -n %s > %s", content, containerFilepath)
-				command.Run(o, "exec", testContainerName, "sh", "-c", cmd)
-				fileDir := ffs.CreateTempDir("finch-test")
-				path := filepath.Join(fileDir, filename)
-				ginkgo.DeferCleanup(os.RemoveAll, fileDir)
+				command.Run(o, "exec", testContainerName(), "sh", "-c", cmd)
+				path := filepath.Join(subjectDir(o, "finch-test"), filename)
 
-				command.Run(o, "cp", containerResource, path)
-				fileShouldExist(path, content)
+				command.Run(o, "cp", containerResource(), path)
+				subjectFileShouldExist(o, path, content)
 			})
 
 			for _, link := range []string{"-L", "--follow-link"} {
@@ -65,22 +61,20 @@ func Cp(o *option.Option) {
 					err := os.Symlink(path, symlink)
 					gomega.Expect(err).ToNot(gomega.HaveOccurred())
 
-					command.Run(o, "cp", link, symlink, containerResource)
-					fileShouldExistInContainer(o, testContainerName, containerFilepath, content)
+					command.Run(o, "cp", link, symlink, containerResource())
+					fileShouldExistInContainer(o, testContainerName(), containerFilepath, content)
 				})
 
 				ginkgo.It(fmt.Sprintf("with %s flag, should be able to copy file from container to host and follow symbolic link",
 					link), func() {
 					cmd := fmt.Sprintf("echo -n %s > %s", content, containerFilepath)
-					command.Run(o, "exec", testContainerName, "sh", "-c", cmd)
+					command.Run(o, "exec", testContainerName(), "sh", "-c", cmd)
 					containerSymlink := filepath.Join("/tmp", "symlink")
-					command.Run(o, "exec", testContainerName, "ln", "-s", containerFilepath, containerSymlink)
-					fileDir := ffs.CreateTempDir("finch-test")
-					path := filepath.Join(fileDir, filename)
-					ginkgo.DeferCleanup(os.RemoveAll, fileDir)
+					command.Run(o, "exec", testContainerName(), "ln", "-s", containerFilepath, containerSymlink)
+					path := filepath.Join(subjectDir(o, "finch-test"), filename)
 
-					command.Run(o, "cp", link, fmt.Sprintf("%s:%s", testContainerName, containerSymlink), path)
-					fileShouldExist(path, content)
+					command.Run(o, "cp", link, fmt.Sprintf("%s:%s", testContainerName(), containerSymlink), path)
+					subjectFileShouldExist(o, path, content)
 				})
 			}
 
@@ -88,42 +82,38 @@ func Cp(o *option.Option) {
 				fileDir := ffs.CreateTempDir("finch-test")
 				ginkgo.DeferCleanup(os.RemoveAll, fileDir)
 
-				command.RunWithoutSuccessfulExit(o, "cp", filepath.Join(fileDir, filename), containerResource)
-				fileShouldNotExistInContainer(o, testContainerName, containerFilepath)
+				command.RunWithoutSuccessfulExit(o, "cp", filepath.Join(fileDir, filename), containerResource())
+				fileShouldNotExistInContainer(o, testContainerName(), containerFilepath)
 			})
 
 			ginkgo.It("should not be able to copy nonexistent file from container to host", func() {
-				fileDir := ffs.CreateTempDir("finch-test")
-				path := filepath.Join(fileDir, filename)
-				ginkgo.DeferCleanup(os.RemoveAll, fileDir)
+				path := filepath.Join(subjectDir(o, "finch-test"), filename)
 
-				command.RunWithoutSuccessfulExit(o, "cp", containerResource, path)
-				fileShouldNotExist(path)
+				command.RunWithoutSuccessfulExit(o, "cp", containerResource(), path)
+				subjectFileShouldNotExist(o, path)
 			})
 		})
 
 		ginkgo.When("the container is not running", func() {
 			ginkgo.It("should be able to copy file from host to container", func() {
-				command.Run(o, "run", "--name", testContainerName, localImages[defaultImage], "sleep", "5")
-				command.Run(o, "stop", testContainerName)
+				command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage], "sleep", "5")
+				command.Run(o, "stop", testContainerName())
 				path := ffs.CreateTempFile(filename, content)
 				ginkgo.DeferCleanup(os.RemoveAll, filepath.Dir(path))
-				command.Run(o, "cp", path, containerResource)
+				command.Run(o, "cp", path, containerResource())
 
 				// Need to run container to cat file, can't exec in stopped container.
 				// Start here will sleep 1s again so we can check file in container.
-				command.Run(o, "container", "start", testContainerName)
-				fileShouldExistInContainer(o, testContainerName, containerFilepath, content)
+				command.Run(o, "container", "start", testContainerName())
+				fileShouldExistInContainer(o, testContainerName(), containerFilepath, content)
 			})
 
 			ginkgo.It("should be able to copy file from container to host", func() {
 				cmd := fmt.Sprintf("echo -n %s > %s", content, containerFilepath)
-				command.Run(o, "run", "--name", testContainerName, localImages[defaultImage], "sh", "-c", cmd)
-				fileDir := ffs.CreateTempDir("finch-test")
-				path := filepath.Join(fileDir, filename)
-				ginkgo.DeferCleanup(os.RemoveAll, fileDir)
-				command.Run(o, "cp", containerResource, path)
-				fileShouldExist(path, content)
+				command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage], "sh", "-c", cmd)
+				path := filepath.Join(subjectDir(o, "finch-test"), filename)
+				command.Run(o, "cp", containerResource(), path)
+				subjectFileShouldExist(o, path, content)
 			})
 		})
 	})
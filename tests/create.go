@@ -11,25 +11,23 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Create tests creating a container.
 func Create(o *option.Option) {
-	ginkgo.Describe("create a container", func() {
+	ginkgo.Describe("create a container", labelsOf("Create"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should create a container and able to start the container", func() {
-			command.Run(o, "create", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
-			status := command.StdoutStr(o, "ps", "-a", "--filter", fmt.Sprintf("name=%s", testContainerName), "--format", "{{.Status}}")
+			command.Run(o, "create", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			status := command.StdoutStr(o, "ps", "-a", "--filter", fmt.Sprintf("name=%s", testContainerName()), "--format", "{{.Status}}")
 			gomega.Expect(status).Should(gomega.Equal("Created"))
 
-			command.Run(o, "start", testContainerName)
-			containerShouldBeRunning(o, testContainerName)
+			command.Run(o, "start", testContainerName())
+			containerShouldBeRunning(o, testContainerName())
 		})
 
 		ginkgo.It("should not create a container if the image doesn't exist", func() {
@@ -0,0 +1,172 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+const (
+	cgroupMountPoint        = "/sys/fs/cgroup"
+	cgroupUnifiedMountPoint = "/sys/fs/cgroup/unified"
+)
+
+var (
+	detectedMu     sync.Mutex
+	cgModes        = map[*option.Option]CGMode{}
+	hostGatewayIPs = map[*option.Option]string{}
+)
+
+// DetectCGMode detects the cgroup mode of the host where the subject runs.
+//
+// The cgroup version reported by the "info" of the subject is used if it's available.
+// Since "info" cannot tell Hybrid from Legacy, the mount table of the init process of the host is also checked.
+// It's read through the subject by a privileged container in the PID namespace of the host,
+// because the host that runs the tests is not where the subject runs in general (e.g., the subject runs in a VM),
+// and the mount table of a container does not tell the cgroup mode of its host. The images used by the tests
+// must have been set up with SetupLocalRegistry. If the mode cannot be told (e.g., the mount table cannot be read
+// in rootless mode), Unavailable is returned. The result is cached per option.
+func DetectCGMode(o *option.Option) CGMode {
+	detectedMu.Lock()
+	defer detectedMu.Unlock()
+	if mode, ok := cgModes[o]; ok {
+		return mode
+	}
+	version := testutil.ProbeCapabilities(o).CgroupVersion
+	mode := Unified
+	if version != "2" {
+		mounts := command.New(o, "run", "--rm", "--privileged", "--pid", "host", localImages[defaultImage], "cat", "/proc/1/mounts").
+			WithoutCheckingExitCode().Run().Out.Contents()
+		mode = cgModeFromInfo(version, cgModeFromMounts(string(mounts)))
+	}
+	cgModes[o] = mode
+	return mode
+}
+
+// cgModeFromInfo returns the cgroup mode given the cgroup version reported by "info" (which may be empty)
+// and the mode derived from the mount table of the host, or Unavailable if they don't agree.
+func cgModeFromInfo(cgroupVersion string, mountMode CGMode) CGMode {
+	switch cgroupVersion {
+	case "2":
+		return Unified
+	case "1":
+		if mountMode == Hybrid || mountMode == Legacy {
+			return mountMode
+		}
+		return Unavailable
+	default:
+		return mountMode
+	}
+}
+
+// cgModeFromMounts returns the cgroup mode given the content of a mount table (e.g., /proc/self/mounts)
+// in the same way as containerd/cgroups does.
+func cgModeFromMounts(mounts string) CGMode {
+	fsTypes := map[string]string{}
+	hasV1 := false
+	scanner := bufio.NewScanner(strings.NewReader(mounts))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mountPoint, fsType := fields[1], fields[2]
+		fsTypes[mountPoint] = fsType
+		if fsType == "cgroup" {
+			hasV1 = true
+		}
+	}
+	switch {
+	case fsTypes[cgroupMountPoint] == "cgroup2":
+		return Unified
+	case fsTypes[cgroupUnifiedMountPoint] == "cgroup2":
+		return Hybrid
+	case hasV1:
+		return Legacy
+	default:
+		return Unavailable
+	}
+}
+
+// DetectHostGatewayIP returns the IP that nerdctl resolves the special IP `host-gateway` to by default,
+// i.e., the first IPv4 address of the host where the subject runs that is not a loopback one.
+//
+// It's not the IP of the subjects that configure it (e.g., host_gateway_ip in the nerdctl.toml of the Finch VM)
+// or resolve it differently (e.g., Docker uses the gateway of the default bridge network),
+// which must be specified with RunOption.DefaultHostGatewayIP instead.
+// The host network is read by running a container with `--network host`,
+// so the images used by the tests must have been set up with SetupLocalRegistry.
+// The result is cached per option.
+func DetectHostGatewayIP(o *option.Option) string {
+	detectedMu.Lock()
+	defer detectedMu.Unlock()
+	if ip, ok := hostGatewayIPs[o]; ok {
+		return ip
+	}
+	addrs := command.New(o, "run", "--rm", "--network", "host", localImages[defaultImage], "ip", "-4", "addr").
+		WithoutCheckingExitCode().Run().Out.Contents()
+	ip := firstHostIP(string(addrs))
+	hostGatewayIPs[o] = ip
+	return ip
+}
+
+// firstHostIP returns the first IPv4 address that is not a loopback one given the output of `ip -4 addr`,
+// which lists the addresses in the same order as nerdctl sees them, or an empty string if there is none.
+func firstHostIP(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "inet" {
+			continue
+		}
+		addr, _, _ := strings.Cut(fields[1], "/")
+		if ip := net.ParseIP(addr); ip != nil && !ip.IsLoopback() {
+			return addr
+		}
+	}
+	return ""
+}
+
+// ipOfHost returns the IP of the host in the content of a hosts file, or an empty string if it's not found.
+func ipOfHost(hosts, host string) string {
+	for _, line := range strings.Split(hosts, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		for _, name := range fields[min(1, len(fields)):] {
+			if name == host {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
+// hostGatewayShouldBeResolved checks that host is mapped to the IP that host-gateway resolves to in the content
+// of a hosts file, which is DefaultHostGatewayIP, or the one detected with DetectHostGatewayIP if it's not set.
+func (o *RunOption) hostGatewayShouldBeResolved(hosts, host string) {
+	ip := ipOfHost(hosts, host)
+	gomega.Expect(ip).ShouldNot(gomega.BeEmpty(), "%s is not found in the hosts file:\n%s", host, hosts)
+	expected := o.DefaultHostGatewayIP
+	if expected == "" {
+		expected = DetectHostGatewayIP(o.BaseOpt)
+		gomega.Expect(expected).ShouldNot(gomega.BeEmpty(), "failed to detect the host gateway IP, specify DefaultHostGatewayIP instead")
+	}
+	gomega.Expect(ip).Should(gomega.Equal(expected))
+}
+
+// cgMode returns CGMode, or detects it with DetectCGMode if the RunOption is created by All.
+func (o *RunOption) cgMode() CGMode {
+	if o.detectCGMode {
+		return DetectCGMode(o.BaseOpt)
+	}
+	return o.CGMode
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"testing"
+)
+
+func TestCGModeFromMounts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		mounts string
+		want   CGMode
+	}{
+		{
+			name:   "Unified",
+			mounts: "proc /proc proc rw 0 0\ncgroup /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime 0 0\n",
+			want:   Unified,
+		},
+		{
+			name: "Hybrid",
+			mounts: "tmpfs /sys/fs/cgroup tmpfs ro 0 0\n" +
+				"cgroup2 /sys/fs/cgroup/unified cgroup2 rw 0 0\n" +
+				"cgroup /sys/fs/cgroup/memory cgroup rw,memory 0 0\n",
+			want: Hybrid,
+		},
+		{
+			name:   "Legacy",
+			mounts: "tmpfs /sys/fs/cgroup tmpfs ro 0 0\ncgroup /sys/fs/cgroup/memory cgroup rw,memory 0 0\n",
+			want:   Legacy,
+		},
+		{name: "Unavailable", mounts: "proc /proc proc rw 0 0\n", want: Unavailable},
+		{name: "Empty", mounts: "", want: Unavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cgModeFromMounts(test.mounts); got != test.want {
+				t.Fatalf("expected %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestCGModeFromInfo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		version   string
+		mountMode CGMode
+		want      CGMode
+	}{
+		{name: "V2", version: "2", mountMode: Unavailable, want: Unified},
+		{name: "V1", version: "1", mountMode: Legacy, want: Legacy},
+		{name: "V1Hybrid", version: "1", mountMode: Hybrid, want: Hybrid},
+		{name: "V1WithoutMounts", version: "1", mountMode: Unavailable, want: Unavailable},
+		{name: "V1WithV2Mounts", version: "1", mountMode: Unified, want: Unavailable},
+		{name: "Unknown", version: "", mountMode: Legacy, want: Legacy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cgModeFromInfo(test.version, test.mountMode); got != test.want {
+				t.Fatalf("expected %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFirstHostIP(t *testing.T) {
+	t.Parallel()
+
+	output := `1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN qlen 1000
+    inet 127.0.0.1/8 scope host lo
+       valid_lft forever preferred_lft forever
+2: eth0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc fq_codel state UP qlen 1000
+    inet 192.168.5.15/24 brd 192.168.5.255 scope global eth0
+       valid_lft forever preferred_lft forever
+3: nerdctl0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc noqueue state UP qlen 1000
+    inet 10.4.0.1/24 brd 10.4.0.255 scope global nerdctl0
+`
+	if got := firstHostIP(output); got != "192.168.5.15" {
+		t.Fatalf("expected 192.168.5.15, got %q", got)
+	}
+	if got := firstHostIP("    inet 127.0.0.1/8 scope host lo\n"); got != "" {
+		t.Fatalf("expected no IP, got %q", got)
+	}
+}
+
+func TestIPOfHost(t *testing.T) {
+	t.Parallel()
+
+	hosts := `# comment mentioning test-host
+127.0.0.1	localhost localhost.localdomain
+::1	localhost ip6-localhost
+192.168.5.2	test-host # host-gateway
+10.4.0.5	abc123 container-name
+`
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "localhost", want: "127.0.0.1"},
+		{host: "ip6-localhost", want: "::1"},
+		{host: "test-host", want: "192.168.5.2"},
+		{host: "container-name", want: "10.4.0.5"},
+		{host: "host-gateway", want: ""},
+		{host: "missing", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ipOfHost(hosts, test.host); got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
@@ -17,7 +17,7 @@ import (
 
 // Events tests "events" command that gets real time events from server, synonyms to "system events" command.
 func Events(o *option.Option) {
-	ginkgo.Describe("get real time events from the server", func() {
+	ginkgo.Describe("get real time events from the server", labelsOf("Events"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 		})
@@ -25,9 +25,8 @@ func Events(o *option.Option) {
 			command.RemoveAll(o)
 		})
 
-		ginkgo.It("should get real time events from command", func() {
-			session := command.RunWithoutWait(o, "system", "events")
-			defer session.Kill()
+		ginkgo.It("should get real time events from command", func(ctx ginkgo.SpecContext) {
+			session := command.New(o, "system", "events").WithContext(ctx).WithoutWait().Run()
 
 			// Give time for the system events to be running and monitoring before pull is called.
 			time.Sleep(5 * time.Second)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
@@ -15,42 +16,58 @@ import (
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/ffs"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Exec tests executing a command in a running container.
 func Exec(o *option.Option) {
-	ginkgo.Describe("execute command in a container", func() {
+	ginkgo.Describe("execute command in a container", labelsOf("Exec"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
-		})
-		// TODO: specifying -t flag will have error in test -> panic: provided file is not a console
 		ginkgo.When("then container is running", func() {
 			ginkgo.BeforeEach(func() {
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 			})
 
 			ginkgo.It("should execute a command in a running container", func() {
 				strEchoed := "hello"
-				output := command.StdoutStr(o, "exec", testContainerName, "echo", strEchoed)
+				output := command.StdoutStr(o, "exec", testContainerName(), "echo", strEchoed)
 				gomega.Expect(output).Should(gomega.Equal(strEchoed))
 			})
 
 			for _, interactive := range []string{"-i", "--interactive", "-i=true", "--interactive=true"} {
 				ginkgo.It(fmt.Sprintf("should output string by piping if %s flag keeps STDIN open", interactive), func() {
 					want := []byte("hello")
-					got := command.New(o, "exec", interactive, testContainerName, "cat").
+					got := command.New(o, "exec", interactive, testContainerName(), "cat").
 						WithStdin(gbytes.BufferWithBytes(want)).Run().Out.Contents()
 					gomega.Expect(got).Should(gomega.Equal(want))
 				})
 			}
 
+			for _, tty := range []string{"-it", "--interactive --tty"} {
+				ginkgo.It(fmt.Sprintf("should allocate a TTY for an interactive shell with %s flags", tty), ginkgo.Label(LabelNeedsTerminal), func() {
+					args := append(append([]string{"exec"}, strings.Fields(tty)...), testContainerName(), "sh")
+					terminal := command.StartInTerminal(o, args...)
+					shouldRespondInTerminal(terminal)
+					terminal.SendLine("exit 3")
+					gomega.Expect(terminal.Wait(terminalTimeout).ExitCode()).To(gomega.Equal(3))
+					containerShouldBeRunning(o, testContainerName())
+				})
+			}
+
+			for _, tty := range []string{"-t", "--tty"} {
+				ginkgo.It(fmt.Sprintf("should allocate a TTY for a command with %s flag", tty), ginkgo.Label(LabelNeedsTerminal), func() {
+					terminal := command.StartInTerminal(o, "exec", tty, testContainerName(), "tty")
+					gomega.Eventually(terminal).WithTimeout(terminalTimeout).Should(gbytes.Say("/dev/pts/"))
+					gomega.Expect(terminal.Wait(terminalTimeout).ExitCode()).To(gomega.Equal(0))
+				})
+			}
+
 			for _, detach := range []string{"-d", "--detach", "-d=true", "--detach=true"} {
 				ginkgo.It(fmt.Sprintf("should execute command in detached mode with %s flag", detach), func() {
-					command.Run(o, "exec", detach, testContainerName, "nc", "-l")
-					processes := command.StdoutStr(o, "exec", testContainerName, "ps", "aux")
+					command.Run(o, "exec", detach, testContainerName(), "nc", "-l")
+					processes := command.StdoutStr(o, "exec", testContainerName(), "ps", "aux")
 					gomega.Expect(processes).Should(gomega.ContainSubstring("nc -l"))
 				})
 			}
@@ -58,7 +75,7 @@ func Exec(o *option.Option) {
 			for _, workDir := range []string{"-w", "--workdir"} {
 				ginkgo.It(fmt.Sprintf("should execute command under directory specified by %s flag", workDir), func() {
 					dir := "/tmp"
-					output := command.StdoutStr(o, "exec", workDir, dir, testContainerName, "pwd")
+					output := command.StdoutStr(o, "exec", workDir, dir, testContainerName(), "pwd")
 					gomega.Expect(output).Should(gomega.Equal(dir))
 				})
 			}
@@ -66,7 +83,7 @@ func Exec(o *option.Option) {
 			for _, env := range []string{"-e", "--env"} {
 				ginkgo.It(fmt.Sprintf("should set the environment variable with %s flag", env), func() {
 					const envPair = "ENV=1"
-					lines := command.StdoutAsLines(o, "exec", env, envPair, testContainerName, "env")
+					lines := command.StdoutAsLines(o, "exec", env, envPair, testContainerName(), "env")
 					gomega.Expect(lines).Should(gomega.ContainElement(envPair))
 				})
 			}
@@ -76,15 +93,15 @@ func Exec(o *option.Option) {
 				envPath := ffs.CreateTempFile("env", envPair)
 				ginkgo.DeferCleanup(os.RemoveAll, filepath.Dir(envPath))
 
-				envOutput := command.StdoutAsLines(o, "exec", "--env-file", envPath, testContainerName, "env")
+				envOutput := command.StdoutAsLines(o, "exec", "--env-file", envPath, testContainerName(), "env")
 				gomega.Expect(envOutput).Should(gomega.ContainElement(envPair))
 			})
 
 			for _, privilegedFlag := range []string{"--privileged", "--privileged=true"} {
-				ginkgo.It(fmt.Sprintf("should execute command in privileged mode with %s flag", privilegedFlag), func() {
-					command.RunWithoutSuccessfulExit(o, "exec", testContainerName, "ip", "link", "add", "dummy1", "type", "dummy")
-					command.Run(o, "exec", privilegedFlag, testContainerName, "ip", "link", "add", "dummy1", "type", "dummy")
-					output := command.StdoutStr(o, "exec", privilegedFlag, testContainerName, "ip", "link")
+				ginkgo.It(fmt.Sprintf("should execute command in privileged mode with %s flag", privilegedFlag), ginkgo.Label(LabelNeedsPrivilege), func() {
+					command.RunWithoutSuccessfulExit(o, "exec", testContainerName(), "ip", "link", "add", "dummy1", "type", "dummy")
+					command.Run(o, "exec", privilegedFlag, testContainerName(), "ip", "link", "add", "dummy1", "type", "dummy")
+					output := command.StdoutStr(o, "exec", privilegedFlag, testContainerName(), "ip", "link")
 					gomega.Expect(output).Should(gomega.ContainSubstring("dummy1"))
 				})
 			}
@@ -97,7 +114,7 @@ func Exec(o *option.Option) {
 					}
 
 					for name, want := range testCases {
-						output := command.StdoutStr(o, "exec", user, name, testContainerName, "id")
+						output := command.StdoutStr(o, "exec", user, name, testContainerName(), "id")
 						// TODO: Remove the Or operator after upgrading the nerdctl dependency to 1.2.1 to only match want[1]
 						gomega.Expect(output).Should(gomega.Or(gomega.Equal(want[0]), gomega.Equal(want[1])))
 					}
@@ -106,8 +123,8 @@ func Exec(o *option.Option) {
 		})
 
 		ginkgo.It("should not execute a command when the container is not running", func() {
-			command.Run(o, "run", "--name", testContainerName, localImages[defaultImage])
-			command.RunWithoutSuccessfulExit(o, "exec", testContainerName)
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			command.RunWithoutSuccessfulExit(o, "exec", testContainerName())
 		})
 	})
 }
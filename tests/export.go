@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/ffs"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// exportedFile is the file that is written to the filesystem of the container exported by the tests.
+const exportedFile = "exported.txt"
+
+// Export tests the "export" command that exports the filesystem of a container to a tar archive.
+func Export(o *option.Option) {
+	ginkgo.Describe("export a container", labelsOf("Export"), func() {
+		var tarFilePath string
+		var tarFileContext string
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o)
+			tarFilePath = ffs.CreateTarFilePath()
+			tarFileContext = filepath.Join(tarFilePath, "../")
+			ginkgo.DeferCleanup(os.RemoveAll, tarFileContext)
+			runContainerToExport(o)
+		})
+
+		ginkgo.It("should export the filesystem of a container to stdout", func() {
+			stdout := command.New(o, "export", testContainerName()).WithStdout(gbytes.NewBuffer()).Run().Out
+			rootfs := filepath.Join(tarFileContext, "rootfs")
+			untar(stdout, rootfs)
+			fileShouldExist(filepath.Join(rootfs, exportedFile), "exported\n")
+		})
+
+		for _, outputOption := range []string{"-o", "--output"} {
+			ginkgo.It(fmt.Sprintf("should export the filesystem of a container with %s option", outputOption), func() {
+				command.Run(o, "export", outputOption, tarFilePath, testContainerName())
+				rootfs := filepath.Join(tarFileContext, "rootfs")
+				untarFile(tarFilePath, rootfs)
+				fileShouldExist(filepath.Join(rootfs, exportedFile), "exported\n")
+			})
+		}
+
+		ginkgo.It("should not export a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "export", "-o", tarFilePath, nonexistentContainerName)
+		})
+	})
+}
+
+// runContainerToExport runs a container named testContainerName() that writes exportedFile to its filesystem and exits.
+func runContainerToExport(o *option.Option) {
+	command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage],
+		"sh", "-c", fmt.Sprintf("echo exported > /%s", exportedFile))
+}
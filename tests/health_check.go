@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/onsi/ginkgo/v2"
@@ -79,15 +80,12 @@ func HealthCheck(o *option.Option) {
 }
 
 func testCliHealthCheckFlags(o *option.Option) {
-	ginkgo.Describe("test container healthcheck flags", func() {
+	ginkgo.Describe("test container healthcheck flags", labelsOf("HealthCheck"), func() {
 		ginkgo.BeforeEach(func() {
 			testutil.RequireNerdctlVersion(o, ">= 2.2.1")
 		})
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		testCases := []testCase{
@@ -147,7 +145,7 @@ func testCliHealthCheckFlags(o *option.Option) {
 				} else if tc.healthCheckFlags != nil {
 					healthCheckArgs = tc.healthCheckFlags.toArgs()
 				}
-				args := append([]string{"run", "-d", "--name", testContainerName}, tc.extraArgs...)
+				args := append([]string{"run", "-d", "--name", testContainerName()}, tc.extraArgs...)
 				args = append(args, healthCheckArgs...)
 				args = append(args, localImages[defaultImage], "sleep", "infinity")
 				if tc.shouldErr {
@@ -163,7 +161,7 @@ func testCliHealthCheckFlags(o *option.Option) {
 }
 
 func testImageHealthCheckFlags(o *option.Option) {
-	ginkgo.Describe("test image healthcheck flags", func() {
+	ginkgo.Describe("test image healthcheck flags", labelsOf("HealthCheck"), func() {
 		var buildContext string
 
 		ginkgo.BeforeEach(func() {
@@ -175,10 +173,7 @@ func testImageHealthCheckFlags(o *option.Option) {
 			`, localImages[defaultImage]))
 
 			ginkgo.DeferCleanup(os.RemoveAll, buildContext)
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		testCases := []testCase{
@@ -222,11 +217,11 @@ func testImageHealthCheckFlags(o *option.Option) {
 				} else if tc.healthCheckFlags != nil {
 					healthCheckArgs = tc.healthCheckFlags.toArgs()
 				}
-				command.Run(o, "build", "-t", testImageName, buildContext)
-				imageShouldExist(o, testImageName)
-				args := append([]string{"run", "-d", "--name", testContainerName}, tc.extraArgs...)
+				command.Run(o, "build", "-t", testImageName(), buildContext)
+				imageShouldExist(o, testImageName())
+				args := append([]string{"run", "-d", "--name", testContainerName()}, tc.extraArgs...)
 				args = append(args, healthCheckArgs...)
-				args = append(args, testImageName)
+				args = append(args, testImageName())
 				command.Run(o, args...)
 				validateInspectHealthCheckFlags(o, tc.expectedFlags)
 			})
@@ -235,15 +230,12 @@ func testImageHealthCheckFlags(o *option.Option) {
 }
 
 func testHealthCheckStatus(o *option.Option) {
-	ginkgo.Describe("test automated container healthcheck status", func() {
+	ginkgo.Describe("test automated container healthcheck status", labelsOf("HealthCheck"), func() {
 		ginkgo.BeforeEach(func() {
 			testutil.RequireNerdctlVersion(o, ">= 2.2.1")
 		})
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		testCases := []testCase{
@@ -405,7 +397,7 @@ func testHealthCheckStatus(o *option.Option) {
 				} else if tc.healthCheckFlags != nil {
 					healthCheckArgs = tc.healthCheckFlags.toArgs()
 				}
-				args := append([]string{"run", "-d", "--name", testContainerName}, tc.extraArgs...)
+				args := append([]string{"run", "-d", "--name", testContainerName()}, tc.extraArgs...)
 				args = append(args, healthCheckArgs...)
 				args = append(args, localImages[defaultImage], "sleep", "infinity")
 				command.Run(o, args...)
@@ -420,15 +412,12 @@ func testHealthCheckStatus(o *option.Option) {
 }
 
 func testHealthCheckStatusNegativeCases(o *option.Option) {
-	ginkgo.Describe("test manual container healthcheck status", func() {
+	ginkgo.Describe("test manual container healthcheck status", labelsOf("HealthCheck"), func() {
 		ginkgo.BeforeEach(func() {
 			testutil.RequireNerdctlVersion(o, ">= 2.2.1")
 		})
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should fail healthcheck on non-existent container", func() {
@@ -437,9 +426,9 @@ func testHealthCheckStatusNegativeCases(o *option.Option) {
 		})
 
 		ginkgo.It("should fail healthcheck on missing healthcheck config", func() {
-			command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 			waitTillContainerStatus(o, "running")
-			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName).Err.Contents()
+			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName()).Err.Contents()
 			gomega.Expect(string(stdErr)).Should(gomega.ContainSubstring("container has no health check configured"))
 		})
 
@@ -448,12 +437,12 @@ func testHealthCheckStatusNegativeCases(o *option.Option) {
 				"--health-cmd", "echo healthy",
 				"--health-interval", "30s",
 			}
-			args := append([]string{"run", "-d", "--name", testContainerName}, healthCheckArgs...)
+			args := append([]string{"run", "-d", "--name", testContainerName()}, healthCheckArgs...)
 			args = append(args, localImages[defaultImage], "sleep", "infinity")
 			command.Run(o, args...)
-			command.Run(o, "pause", testContainerName)
+			command.Run(o, "pause", testContainerName())
 			waitTillContainerStatus(o, "paused")
-			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName).Err.Contents()
+			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName()).Err.Contents()
 			gomega.Expect(string(stdErr)).Should(gomega.ContainSubstring("container is not running (status: paused)"))
 		})
 
@@ -462,11 +451,11 @@ func testHealthCheckStatusNegativeCases(o *option.Option) {
 				"--health-cmd", "echo healthy",
 				"--health-interval", "30s",
 			}
-			args := append([]string{"run", "-d", "--name", testContainerName}, healthCheckArgs...)
+			args := append([]string{"run", "-d", "--name", testContainerName()}, healthCheckArgs...)
 			args = append(args, localImages[defaultImage], "sleep", "2s")
 			command.Run(o, args...)
 			waitTillContainerStatus(o, "exited")
-			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName).Err.Contents()
+			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName()).Err.Contents()
 			gomega.Expect(string(stdErr)).Should(gomega.ContainSubstring("container is not running (status: stopped)"))
 		})
 
@@ -475,10 +464,10 @@ func testHealthCheckStatusNegativeCases(o *option.Option) {
 				"--health-cmd", "echo healthy",
 				"--health-interval", "30s",
 			}
-			args := append([]string{"create", "--name", testContainerName}, healthCheckArgs...)
+			args := append([]string{"create", "--name", testContainerName()}, healthCheckArgs...)
 			args = append(args, localImages[defaultImage], "sleep", "infinity")
 			command.Run(o, args...)
-			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName).Err.Contents()
+			stdErr := command.RunWithoutSuccessfulExit(o, "container", "healthcheck", testContainerName()).Err.Contents()
 			gomega.Expect(string(stdErr)).Should(gomega.ContainSubstring("failed to get container task: no running task found"))
 		})
 	})
@@ -486,43 +475,43 @@ func testHealthCheckStatusNegativeCases(o *option.Option) {
 
 func validateInspectHealthCheckFlags(o *option.Option, expect *healthCheckFlags) {
 	if expect == nil {
-		inspectHealthCheck := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck}}", testContainerName)
+		inspectHealthCheck := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck}}", testContainerName())
 		gomega.Expect(inspectHealthCheck).Should(gomega.Equal("<nil>"))
 		return
 	}
 	if expect.cmd != "" {
-		inspectTest := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Test}}", testContainerName)
+		inspectTest := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Test}}", testContainerName())
 		gomega.Expect(inspectTest).Should(gomega.Equal(expect.cmd))
 	}
 	if expect.interval != 0 {
-		inspectHealthInterval := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Interval}}", testContainerName)
+		inspectHealthInterval := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Interval}}", testContainerName())
 		gomega.Expect(inspectHealthInterval).Should(gomega.Equal(fmt.Sprintf("%ds", expect.interval)))
 	}
 	if expect.timeout != 0 {
-		inspectTimeout := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Timeout}}", testContainerName)
+		inspectTimeout := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Timeout}}", testContainerName())
 		gomega.Expect(inspectTimeout).Should(gomega.Equal(fmt.Sprintf("%ds", expect.timeout)))
 	}
 	if expect.retries != 0 {
-		inspectRetries := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Retries}}", testContainerName)
+		inspectRetries := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.Retries}}", testContainerName())
 		gomega.Expect(inspectRetries).Should(gomega.Equal(fmt.Sprintf("%d", expect.retries)))
 	}
 	if expect.startPeriod != 0 {
-		inspectStartPeriod := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.StartPeriod}}", testContainerName)
+		inspectStartPeriod := command.StdoutStr(o, "inspect", "--format", "{{.Config.Healthcheck.StartPeriod}}", testContainerName())
 		gomega.Expect(inspectStartPeriod).Should(gomega.Equal(fmt.Sprintf("%ds", expect.startPeriod)))
 	}
 }
 
 func validateInspectHealthCheckStatus(o *option.Option, expect *healthCheckStatus) {
 	if expect == nil {
-		inspectHealthStatus := command.StdoutStr(o, "inspect", "--format", "{{.State.Health}}", testContainerName)
+		inspectHealthStatus := command.StdoutStr(o, "inspect", "--format", "{{.State.Health}}", testContainerName())
 		gomega.Expect(inspectHealthStatus).Should(gomega.Equal("<nil>"))
 		return
 	}
 	if expect.status != "" {
-		inspectHealthStatus := command.StdoutStr(o, "inspect", "--format", "{{.State.Health.Status}}", testContainerName)
+		inspectHealthStatus := command.StdoutStr(o, "inspect", "--format", "{{.State.Health.Status}}", testContainerName())
 		gomega.Expect(inspectHealthStatus).Should(gomega.Equal(expect.status))
 	}
-	inspectFailingStreak, err := strconv.Atoi(command.StdoutStr(o, "inspect", "--format", "{{.State.Health.FailingStreak}}", testContainerName))
+	inspectFailingStreak, err := strconv.Atoi(command.StdoutStr(o, "inspect", "--format", "{{.State.Health.FailingStreak}}", testContainerName()))
 	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 	if expect.failingStreak > 0 {
 		gomega.Expect(inspectFailingStreak).Should(gomega.BeNumerically(">=", expect.failingStreak))
@@ -530,28 +519,23 @@ func validateInspectHealthCheckStatus(o *option.Option, expect *healthCheckStatu
 		gomega.Expect(inspectFailingStreak).Should(gomega.BeNumerically("==", expect.failingStreak))
 	}
 	if expect.maxLogEntries > 0 {
-		inspectLogEntries, err := strconv.Atoi(command.StdoutStr(o, "inspect", "--format", "{{len .State.Health.Log}}", testContainerName))
+		inspectLogEntries, err := strconv.Atoi(command.StdoutStr(o, "inspect", "--format", "{{len .State.Health.Log}}", testContainerName()))
 		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
 		gomega.Expect(inspectLogEntries).Should(gomega.BeNumerically("<=", expect.maxLogEntries))
 	}
 	if expect.logContainsStr != "" {
-		inspectLog := command.StdoutStr(o, "inspect", "--format", "{{(index .State.Health.Log 0).Output}}", testContainerName)
+		inspectLog := command.StdoutStr(o, "inspect", "--format", "{{(index .State.Health.Log 0).Output}}", testContainerName())
 		gomega.Expect(inspectLog).Should(gomega.ContainSubstring(expect.logContainsStr))
 	}
 	if expect.minLogLen > 0 {
-		inspectLogLen := len(command.StdoutStr(o, "inspect", "--format", "{{(index .State.Health.Log 0).Output}}", testContainerName))
+		inspectLogLen := len(command.StdoutStr(o, "inspect", "--format", "{{(index .State.Health.Log 0).Output}}", testContainerName()))
 		gomega.Expect(inspectLogLen).Should(gomega.BeNumerically(">=", expect.minLogLen))
 	}
 }
 
 func waitTillContainerStatus(o *option.Option, status string) {
-	retryCount := 20
-	retryInterval := time.Second
-	for i := 1; i <= retryCount; i++ {
-		time.Sleep(retryInterval)
-		if command.StdoutStr(o, "inspect", "--format", "{{.State.Status}}", testContainerName) == status {
-			return
-		}
-	}
-	ginkgo.Fail(fmt.Sprintf("container is still not in status \"%s\" after %d attempts", status, retryCount))
+	session := command.New(o, "inspect", "--format", "{{.State.Status}}", testContainerName()).
+		WithRetry(20, time.Second, command.RetryUntilStdout(status)).Run()
+	gomega.Expect(strings.TrimSpace(string(session.Out.Contents()))).Should(gomega.Equal(status),
+		fmt.Sprintf("container is still not in status \"%s\" after 20 attempts", status))
 }
@@ -19,7 +19,7 @@ import (
 
 // ImageHistory tests "image history" command that shows the history of an image.
 func ImageHistory(o *option.Option) {
-	ginkgo.Describe("show the history of an image", func() {
+	ginkgo.Describe("show the history of an image", labelsOf("ImageHistory"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 			pullImage(o, localImages[defaultImage])
@@ -54,9 +54,9 @@ func ImageHistory(o *option.Option) {
 			`, localImages[defaultImage], text))
 			ginkgo.DeferCleanup(os.RemoveAll, buildContext)
 
-			command.Run(o, "build", "-t", testImageName, buildContext)
-			gomega.Expect(command.StdoutStr(o, "image", "history", testImageName)).ShouldNot(gomega.ContainSubstring(text))
-			gomega.Expect(command.StdoutStr(o, "image", "history", "--no-trunc", testImageName)).Should(gomega.ContainSubstring(text))
+			command.Run(o, "build", "-t", testImageName(), buildContext)
+			gomega.Expect(command.StdoutStr(o, "image", "history", testImageName())).ShouldNot(gomega.ContainSubstring(text))
+			gomega.Expect(command.StdoutStr(o, "image", "history", "--no-trunc", testImageName())).Should(gomega.ContainSubstring(text))
 		})
 	})
 }
@@ -13,7 +13,7 @@ import (
 
 // ImageInspect tests "image inspect" command that displays detailed information on one or more images.
 func ImageInspect(o *option.Option) {
-	ginkgo.Describe("display detailed information on one or more images", func() {
+	ginkgo.Describe("display detailed information on one or more images", labelsOf("ImageInspect"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 			pullImage(o, localImages[defaultImage])
@@ -18,7 +18,7 @@ func ImagePrune(o *option.Option) {
 	// Currently, nerdctl image prune requires --all to be specified.
 	// REF - https://github.com/containerd/nerdctl#whale-nerdctl-image-prune
 	// TODO: Add a test case to only prune dangling images after `--all` is not required for `image prune`.
-	ginkgo.Describe("Remove unused images", func() {
+	ginkgo.Describe("Remove unused images", labelsOf("ImagePrune"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 			pullImage(o, localImages[defaultImage])
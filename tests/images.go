@@ -14,7 +14,7 @@ import (
 // Images tests functionality of `images` command that lists container images.
 func Images(o *option.Option) {
 	const sha256RegexTruncated = `^[a-f0-9]{12}$`
-	ginkgo.Describe("list container images", ginkgo.Ordered, func() {
+	ginkgo.Describe("list container images", labelsOf("Images"), ginkgo.Serial, ginkgo.Ordered, func() {
 		testImageName := "fn-test-images-cmd:latest"
 		ginkgo.BeforeAll(func() {
 			pullImage(o, localImages[defaultImage])
@@ -0,0 +1,79 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/ffs"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// Import tests the "import" command that creates an image from a tar archive of a filesystem, e.g., one created by "export".
+func Import(o *option.Option) {
+	ginkgo.Describe("import an image", labelsOf("Import"), ginkgo.Serial, func() {
+		var tarFilePath string
+		ginkgo.BeforeEach(func() {
+			command.RemoveAll(o)
+			tarFilePath = ffs.CreateTarFilePath()
+			ginkgo.DeferCleanup(os.RemoveAll, filepath.Dir(tarFilePath))
+			runContainerToExport(o)
+			command.Run(o, "export", "-o", tarFilePath, testContainerName())
+			command.Run(o, "rm", testContainerName())
+		})
+		ginkgo.AfterEach(func() {
+			command.RemoveAll(o)
+		})
+
+		ginkgo.It("should import an exported filesystem from a file", func() {
+			command.Run(o, "import", tarFilePath, testImageName())
+			imageShouldExist(o, testImageName())
+			gomega.Expect(inspect.Image(o, testImageName()).RootFS.Layers).To(gomega.HaveLen(1))
+			gomega.Expect(command.StdoutStr(o, "run", "--rm", testImageName(), "cat", "/"+exportedFile)).To(gomega.Equal("exported"))
+		})
+
+		ginkgo.It("should import an exported filesystem from stdin", func() {
+			tarFile, err := os.Open(filepath.Clean(tarFilePath))
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			ginkgo.DeferCleanup(tarFile.Close)
+			command.New(o, "import", "-", testImageName()).WithStdin(tarFile).Run()
+			gomega.Expect(command.StdoutStr(o, "run", "--rm", testImageName(), "cat", "/"+exportedFile)).To(gomega.Equal("exported"))
+		})
+
+		ginkgo.It("should apply the Dockerfile instructions to the image config with --change flag", func() {
+			testutil.RequireCapability(o, "import --change")
+			command.Run(o, "import", "--change", `CMD ["cat", "/exported.txt"]`, "--change", "ENV FOO=bar", tarFilePath, testImageName())
+			config := inspect.Image(o, testImageName()).Config
+			gomega.Expect(config.Cmd).To(gomega.Equal([]string{"cat", "/" + exportedFile}))
+			gomega.Expect(config.Env).To(gomega.ContainElement("FOO=bar"))
+			gomega.Expect(command.StdoutStr(o, "run", "--rm", testImageName())).To(gomega.Equal("exported"))
+		})
+
+		ginkgo.It("should set the commit message with --message flag", func() {
+			testutil.RequireCapability(o, "import --message")
+			const message = "test import message"
+			command.Run(o, "import", "--message", message, tarFilePath, testImageName())
+			gomega.Expect(inspect.Image(o, testImageName()).Comment).To(gomega.Equal(message))
+		})
+
+		ginkgo.It("should not import a nonexistent file", func() {
+			command.RunWithoutSuccessfulExit(o, "import", filepath.Join(filepath.Dir(tarFilePath), "nonexistent.tar"), testImageName())
+			imageShouldNotExist(o, testImageName())
+		})
+
+		ginkgo.It("should not import a file that is not a tar archive", func() {
+			notTar := filepath.Join(filepath.Dir(tarFilePath), "not-a-tar.txt")
+			ffs.WriteFile(notTar, "not a tar archive")
+			command.RunWithoutSuccessfulExit(o, "import", notTar, testImageName())
+			imageShouldNotExist(o, testImageName())
+		})
+	})
+}
@@ -9,16 +9,14 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Info tests "info" command that displays system-wide information, synonyms to "system info" command.
 func Info(o *option.Option) {
-	ginkgo.Describe("display system-wide information", func() {
+	ginkgo.Describe("display system-wide information", labelsOf("Info"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should display system-wide information", func() {
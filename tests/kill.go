@@ -10,28 +10,26 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Kill tests killing a running container.
 func Kill(o *option.Option) {
-	ginkgo.Describe("kill a container", func() {
+	ginkgo.Describe("kill a container", labelsOf("Kill"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.When("the container is running", func() {
 			ginkgo.BeforeEach(func() {
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 			})
 
 			ginkgo.It("should kill the running container", func() {
-				containerShouldBeRunning(o, testContainerName)
-				command.Run(o, "kill", testContainerName)
-				command.RunWithoutSuccessfulExit(o, "exec", testContainerName, "echo", "foo")
-				containerShouldNotBeRunning(o, testContainerName)
+				containerShouldBeRunning(o, testContainerName())
+				command.Run(o, "kill", testContainerName())
+				command.RunWithoutSuccessfulExit(o, "exec", testContainerName(), "echo", "foo")
+				containerShouldNotBeRunning(o, testContainerName())
 			})
 
 			for _, signal := range []string{"-s", "--signal"} {
@@ -39,9 +37,9 @@ func Kill(o *option.Option) {
 				// https://stackoverflow.com/questions/45148381/why-cant-i-ctrl-c-a-sleep-infinity-in-docker-when-it-runs-as-pid-1
 				for _, term := range []string{"SIGTERM", "TERM"} {
 					ginkgo.It(fmt.Sprintf("should not kill the running container with %s %s", signal, term), func() {
-						containerShouldBeRunning(o, testContainerName)
-						command.Run(o, "kill", signal, term, testContainerName)
-						containerShouldBeRunning(o, testContainerName)
+						containerShouldBeRunning(o, testContainerName())
+						command.Run(o, "kill", signal, term, testContainerName())
+						containerShouldBeRunning(o, testContainerName())
 					})
 				}
 			}
@@ -0,0 +1,120 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// The labels that the specs are decorated with. They can be used to select the specs with the --ginkgo.label-filter flag,
+// e.g., --ginkgo.label-filter="container && !slow", or to compose a suite with All.
+const (
+	// LabelImage marks the specs of the commands that manage images.
+	LabelImage = "image"
+	// LabelContainer marks the specs of the commands that manage containers.
+	LabelContainer = "container"
+	// LabelNetwork marks the specs of the commands that manage networks.
+	LabelNetwork = "network"
+	// LabelVolume marks the specs of the commands that manage volumes.
+	LabelVolume = "volume"
+	// LabelCompose marks the specs of the compose commands.
+	LabelCompose = "compose"
+	// LabelBuild marks the specs that build images.
+	LabelBuild = "build"
+	// LabelRegistry marks the specs that talk to a registry other than the local one set up by SetupLocalRegistry.
+	LabelRegistry = "registry"
+	// LabelSystem marks the specs of the commands that inspect the subject itself, e.g., info and events.
+	LabelSystem = "system"
+
+	// LabelSlow marks the specs that take a long time to run, e.g., because they wait for timers to fire.
+	LabelSlow = "slow"
+	// LabelNeedsNetwork marks the specs that need access to the internet.
+	LabelNeedsNetwork = "needs-network"
+	// LabelNeedsPrivilege marks the specs that need privileges that a rootless subject may not have.
+	LabelNeedsPrivilege = "needs-privilege"
+	// LabelNeedsTerminal marks the specs that run the subject in a pseudo-terminal (see command.StartInTerminal).
+	LabelNeedsTerminal = "needs-terminal"
+
+	// LabelNeedsCgroup marks the specs that need the host to have cgroup mounted, and cgroup v2 if the subject is rootless.
+	LabelNeedsCgroup = "needs-cgroup"
+	// LabelNeedsCgroupV2 marks the specs that need the host to use cgroup v2 (i.e., Unified).
+	LabelNeedsCgroupV2 = "needs-cgroup-v2"
+	// LabelNotWindows marks the specs that do not work when the tests run on Windows.
+	LabelNotWindows = "not-windows"
+)
+
+// suiteLabels are the labels of each test suite in this package, keyed by the name of the function that registers it.
+// The specs whose requirements differ from the rest of their suite (e.g., LabelNeedsPrivilege) are labelled individually.
+var suiteLabels = map[string][]string{
+	"Pull":           {LabelImage},
+	"Rm":             {LabelContainer},
+	"Rmi":            {LabelImage},
+	"Run":            {LabelContainer, LabelSlow},
+	"Start":          {LabelContainer},
+	"Stop":           {LabelContainer},
+	"Cp":             {LabelContainer},
+	"Tag":            {LabelImage},
+	"Save":           {LabelImage},
+	"Load":           {LabelImage},
+	"Build":          {LabelBuild, LabelImage, LabelSlow, LabelNeedsNetwork},
+	"Push":           {LabelImage, LabelRegistry},
+	"Images":         {LabelImage},
+	"ComposeBuild":   {LabelCompose, LabelBuild, LabelSlow},
+	"ComposeDown":    {LabelCompose},
+	"ComposeKill":    {LabelCompose},
+	"ComposePs":      {LabelCompose},
+	"ComposePull":    {LabelCompose},
+	"ComposeLogs":    {LabelCompose},
+	"Create":         {LabelContainer},
+	"Port":           {LabelContainer},
+	"Kill":           {LabelContainer},
+	"Restart":        {LabelContainer, LabelSlow},
+	"Stats":          {LabelContainer},
+	"BuilderPrune":   {LabelBuild},
+	"Exec":           {LabelContainer},
+	"Attach":         {LabelContainer, LabelNeedsTerminal},
+	"Logs":           {LabelContainer, LabelSlow},
+	"Login":          {LabelRegistry},
+	"Logout":         {LabelRegistry},
+	"VolumeCreate":   {LabelVolume},
+	"VolumeInspect":  {LabelVolume},
+	"VolumeLs":       {LabelVolume},
+	"VolumeRm":       {LabelVolume},
+	"VolumePrune":    {LabelVolume},
+	"ImageHistory":   {LabelImage},
+	"ImageInspect":   {LabelImage},
+	"ImagePrune":     {LabelImage},
+	"Info":           {LabelSystem},
+	"Events":         {LabelSystem, LabelSlow},
+	"Inspect":        {LabelContainer},
+	"NetworkCreate":  {LabelNetwork},
+	"NetworkInspect": {LabelNetwork},
+	"NetworkLs":      {LabelNetwork},
+	"NetworkRm":      {LabelNetwork},
+	"NetworkPrune":   {LabelNetwork},
+	"Pause":          {LabelContainer},
+	"Unpause":        {LabelContainer},
+	"Wait":           {LabelContainer},
+	"Rename":         {LabelContainer},
+	"Top":            {LabelContainer},
+	"Update":         {LabelContainer},
+	"Commit":         {LabelContainer, LabelImage},
+	"Export":         {LabelContainer},
+	"Import":         {LabelImage},
+	"Ps":             {LabelContainer},
+	"HealthCheck":    {LabelContainer, LabelSlow},
+	"SystemPrune":    {LabelSystem, LabelContainer, LabelImage, LabelNetwork, LabelVolume, LabelBuild},
+}
+
+// labelsOf returns the labels of the test suite as a Ginkgo decorator.
+// It panics if the suite is unknown, which is most likely a typo, so that the suite is not left unlabelled.
+func labelsOf(suite string) ginkgo.Labels {
+	labels, ok := suiteLabels[suite]
+	if !ok {
+		panic(fmt.Sprintf("test suite %q is not found in suiteLabels", suite))
+	}
+	return ginkgo.Label(labels...)
+}
@@ -17,7 +17,7 @@ import (
 
 // Load tests loading images from tar file or stdin.
 func Load(o *option.Option) {
-	ginkgo.Describe("load an image", func() {
+	ginkgo.Describe("load an image", labelsOf("Load"), ginkgo.Serial, func() {
 		var tarFilePath string
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
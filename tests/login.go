@@ -6,21 +6,18 @@ package tests
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega/gbytes"
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/ffs"
-	"github.com/runfinch/common-tests/fnet"
 	"github.com/runfinch/common-tests/option"
 )
 
 // Login tests logging in a container registry.
 func Login(o *option.Option) {
-	ginkgo.Describe("log in a container registry", func() {
+	ginkgo.Describe("log in a container registry", labelsOf("Login"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 		})
@@ -31,7 +28,6 @@ func Login(o *option.Option) {
 			var registry string
 			var tag string
 			ginkgo.BeforeEach(func() {
-				filename := "htpasswd"
 				// The htpasswd is generated by
 				// `<subject> run --entrypoint htpasswd public.ecr.aws/docker/library/httpd:2 -Bbn testUser testPassword`.
 				// We don't want to generate it on the fly because:
@@ -40,29 +36,7 @@ func Login(o *option.Option) {
 				// 3. It's not the thing we want to validate by the functional tests. We only want the output produced by it.
 				//nolint:gosec // This password is only used for testing purpose.
 				htpasswd := "testUser:$2y$05$wE0sj3r9O9K9q7R0MXcfPuIerl/06L1IsxXkCuUr3QZ8lHWwicIdS"
-				htpasswdDir := filepath.Dir(ffs.CreateTempFile(filename, htpasswd))
-				ginkgo.DeferCleanup(os.RemoveAll, htpasswdDir)
-				port := fnet.GetFreePort()
-				containerID := command.StdoutStr(o, "run",
-					"-dp", fmt.Sprintf("%d:5000", port),
-					"--name", "registry",
-					"-v", fmt.Sprintf("%s:/auth", htpasswdDir),
-					"-e", "REGISTRY_AUTH=htpasswd",
-					"-e", "REGISTRY_AUTH_HTPASSWD_REALM=Registry Realm",
-					"-e", fmt.Sprintf("REGISTRY_AUTH_HTPASSWD_PATH=/auth/%s", filename),
-					registryImage)
-				// Wait for container to be running
-				tries := 0
-				for command.StdoutStr(o, "inspect", "-f", "{{.State.Running}}", containerID) != "true" {
-					if tries >= 5 {
-						ginkgo.Fail("Registry container failed to start after 5 seconds")
-					}
-					time.Sleep(1 * time.Second)
-					tries++
-				}
-				// Wait for registry service to be ready
-				time.Sleep(10 * time.Second)
-				registry = fmt.Sprintf(`localhost:%d`, port)
+				registry = startRegistry(o, htpasswd)
 				tag = fmt.Sprintf(`%s/test-login:tag`, registry)
 				buildContext := ffs.CreateBuildContext(fmt.Sprintf(`FROM %s
 		CMD ["echo", "bar"]
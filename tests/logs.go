@@ -14,34 +14,32 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Logs tests fetching logs of a container.
 func Logs(o *option.Option) {
-	ginkgo.Describe("fetch logs of a container", func() {
+	ginkgo.Describe("fetch logs of a container", labelsOf("Logs"), func() {
 		const foo = "foo"
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.When("the container is not running and has one line of logs", func() {
 			ginkgo.BeforeEach(func() {
 				// Currently, only containers created with `run -d` are supported.
 				// https://github.com/containerd/nerdctl#whale-nerdctl-logs
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "echo", foo)
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "echo", foo)
 			})
 
 			ginkgo.It("should fetch the logs of a container", func() {
-				output := command.StdoutStr(o, "logs", testContainerName)
+				output := command.StdoutStr(o, "logs", testContainerName())
 				gomega.Expect(output).Should(gomega.Equal(foo))
 			})
 
 			for _, timestamps := range []string{"-t", "--timestamps"} {
 				ginkgo.It(fmt.Sprintf("should include timestamp with %s flag", timestamps), func() {
-					output := command.StdoutStr(o, "logs", timestamps, testContainerName)
+					output := command.StdoutStr(o, "logs", timestamps, testContainerName())
 					// `logs --timestamps` command will add an RFC3339Nano timestamp,
 					// for example 2014-09-16T06:17:46.000000000Z, to each log entry.
 					// "2006-01-02" is a golang common layout which specifies the format to be yyyy-MM-dd.
@@ -52,17 +50,17 @@ func Logs(o *option.Option) {
 
 			ginkgo.It("should show log message depending on a relative time with --since flag", func() {
 				time.Sleep(2 * time.Second)
-				output := command.StdoutStr(o, "logs", "--since", "1s", testContainerName)
+				output := command.StdoutStr(o, "logs", "--since", "1s", testContainerName())
 				gomega.Expect(output).Should(gomega.BeEmpty())
-				output = command.StdoutStr(o, "logs", "--since", "5s", testContainerName)
+				output = command.StdoutStr(o, "logs", "--since", "5s", testContainerName())
 				gomega.Expect(output).Should(gomega.Equal(foo))
 			})
 
 			ginkgo.It("should show log message depending on a relative time with --until flag", func() {
 				time.Sleep(2 * time.Second)
-				output := command.StdoutStr(o, "logs", "--until", "1s", testContainerName)
+				output := command.StdoutStr(o, "logs", "--until", "1s", testContainerName())
 				gomega.Expect(output).Should(gomega.Equal(foo))
-				output = command.StdoutStr(o, "logs", "--until", "5s", testContainerName)
+				output = command.StdoutStr(o, "logs", "--until", "5s", testContainerName())
 				gomega.Expect(output).Should(gomega.BeEmpty())
 			})
 		})
@@ -70,16 +68,16 @@ func Logs(o *option.Option) {
 		ginkgo.When("the container is not running and has multiple lines of logs", func() {
 			const bar = "bar"
 			ginkgo.BeforeEach(func() {
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage],
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage],
 					"sh", "-c", fmt.Sprintf("echo %s; echo %s", foo, bar))
 			})
 
 			for _, tail := range []string{"-n", "--tail"} {
 				ginkgo.It(fmt.Sprintf("should show number of lines from end of the logs with %s flag", tail), func() {
 					expectedOutput := fmt.Sprintf("%s\n%s", foo, bar)
-					output := command.StdoutStr(o, "logs", tail, "1", testContainerName)
+					output := command.StdoutStr(o, "logs", tail, "1", testContainerName())
 					gomega.Expect(output).Should(gomega.Equal(bar))
-					output = command.StdoutStr(o, "logs", tail, "all", testContainerName)
+					output = command.StdoutStr(o, "logs", tail, "all", testContainerName())
 					gomega.Expect(output).Should(gomega.Equal(expectedOutput))
 				})
 			}
@@ -87,16 +85,15 @@ func Logs(o *option.Option) {
 
 		ginkgo.When("the container is running", func() {
 			ginkgo.BeforeEach(func() {
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 			})
 
 			for _, follow := range []string{"-f", "--follow"} {
-				ginkgo.It(fmt.Sprintf("should follow log output with %s flag", follow), func() {
+				ginkgo.It(fmt.Sprintf("should follow log output with %s flag", follow), func(ctx ginkgo.SpecContext) {
 					const newLog = "hello"
-					session := command.RunWithoutWait(o, "logs", follow, testContainerName)
-					defer session.Kill()
+					session := command.New(o, "logs", follow, testContainerName()).WithContext(ctx).WithoutWait().Run()
 					gomega.Expect(session.Out.Contents()).Should(gomega.BeEmpty())
-					command.Run(o, "exec", testContainerName, "sh", "-c", fmt.Sprintf("echo %s >> /proc/1/fd/1", newLog))
+					command.Run(o, "exec", testContainerName(), "sh", "-c", fmt.Sprintf("echo %s >> /proc/1/fd/1", newLog))
 					// allow propagation time
 					gomega.Eventually(func(session *gexec.Session) string {
 						return strings.TrimSpace(string(session.Out.Contents()))
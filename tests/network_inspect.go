@@ -9,16 +9,14 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // NetworkInspect tests the "network inspect" command that displays detailed information on one or more networks.
 func NetworkInspect(o *option.Option) {
-	ginkgo.Describe("display detailed information on network", func() {
+	ginkgo.Describe("display detailed information on network", labelsOf("NetworkInspect"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should display detailed information about one network", func() {
@@ -27,9 +25,9 @@ func NetworkInspect(o *option.Option) {
 		})
 
 		ginkgo.It("should display detailed information on multiple networks", func() {
-			command.Run(o, "network", "create", testNetwork)
-			lines := command.StdoutAsLines(o, "network", "inspect", bridgeNetwork, testNetwork, "--format", "{{.Name}}")
-			gomega.Expect(lines).Should(gomega.ConsistOf(bridgeNetwork, testNetwork))
+			command.Run(o, "network", "create", testNetwork())
+			lines := command.StdoutAsLines(o, "network", "inspect", bridgeNetwork, testNetwork(), "--format", "{{.Name}}")
+			gomega.Expect(lines).Should(gomega.ConsistOf(bridgeNetwork, testNetwork()))
 		})
 	})
 }
@@ -11,16 +11,14 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // NetworkLs tests the "network ls" command that list networks.
 func NetworkLs(o *option.Option) {
-	ginkgo.Describe("list networks", func() {
+	ginkgo.Describe("list networks", labelsOf("NetworkLs"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should list all the networks", func() {
@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// NetworkPrune tests the "network prune" command that removes all unused networks.
+func NetworkPrune(o *option.Option) {
+	ginkgo.Describe("remove all unused networks", labelsOf("NetworkPrune"), ginkgo.Serial, func() {
+		ginkgo.BeforeEach(func() {
+			if o.CleanupLabel() != "" {
+				ginkgo.Skip("network prune removes the networks that are not created by the tests")
+			}
+			command.RemoveAll(o)
+			command.Run(o, "network", "create", testNetwork())
+		})
+		ginkgo.AfterEach(func() {
+			command.RemoveAll(o)
+		})
+
+		for _, force := range []string{"--force", "-f"} {
+			ginkgo.It(fmt.Sprintf("should remove an unused network without prompting for confirmation with %s flag", force), func() {
+				command.Run(o, "network", "prune", force)
+				networkShouldNotExist(o, testNetwork())
+			})
+		}
+
+		ginkgo.It("should remove an unused network with inputting y in prompt confirmation", func() {
+			command.New(o, "network", "prune").WithStdin(gbytes.BufferWithBytes([]byte("y"))).Run()
+			networkShouldNotExist(o, testNetwork())
+		})
+
+		ginkgo.It("should not remove an unused network with inputting n in prompt confirmation", func() {
+			command.New(o, "network", "prune").WithStdin(gbytes.BufferWithBytes([]byte("n"))).Run()
+			networkShouldExist(o, testNetwork())
+		})
+
+		ginkgo.It("should not remove a network used by a container", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), "--network", testNetwork(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "network", "prune", "--force")
+			networkShouldExist(o, testNetwork())
+		})
+
+		ginkgo.It("should not remove the default bridge network", func() {
+			command.Run(o, "network", "prune", "--force")
+			networkShouldExist(o, bridgeNetwork)
+		})
+
+		ginkgo.When("filtering the networks to remove", func() {
+			ginkgo.BeforeEach(func() {
+				testutil.RequireCapability(o, "network prune --filter")
+			})
+
+			ginkgo.It("should only remove the networks with the label with --filter label=", func() {
+				const labelledNetwork = "test-network-labelled"
+				command.Run(o, "network", "create", "--label", "prune=true", labelledNetwork)
+				command.Run(o, "network", "prune", "--force", "--filter", "label=prune=true")
+				networkShouldNotExist(o, labelledNetwork)
+				networkShouldExist(o, testNetwork())
+			})
+
+			ginkgo.It("should only remove the networks created before the timestamp with --filter until=", func() {
+				const newNetwork = "test-network-new"
+				time.Sleep(5 * time.Second)
+				command.Run(o, "network", "create", newNetwork)
+				command.Run(o, "network", "prune", "--force", "--filter", "until=3s")
+				networkShouldNotExist(o, testNetwork())
+				networkShouldExist(o, newNetwork)
+			})
+		})
+	})
+}
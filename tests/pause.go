@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// Pause tests the "pause" command that suspends all processes within one or more containers.
+func Pause(o *option.Option) {
+	ginkgo.Describe("pause a container", labelsOf("Pause"), func() {
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o)
+		})
+
+		ginkgo.It("should pause a running container", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "pause", testContainerName())
+			state := inspect.Container(o, testContainerName()).State
+			gomega.Expect(state.Status).To(gomega.Equal("paused"))
+			gomega.Expect(state.Paused).To(gomega.BeTrue())
+			command.RunWithoutSuccessfulExit(o, "exec", testContainerName(), "echo", "foo")
+		})
+
+		ginkgo.It("should pause multiple running containers", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "run", "-d", "--name", testContainerName2(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "pause", testContainerName(), testContainerName2())
+			gomega.Expect(inspect.Container(o, testContainerName()).State.Status).To(gomega.Equal("paused"))
+			gomega.Expect(inspect.Container(o, testContainerName2()).State.Status).To(gomega.Equal("paused"))
+		})
+
+		ginkgo.It("should not pause a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "pause", nonexistentContainerName)
+		})
+
+		ginkgo.It("should not pause a stopped container", func() {
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			command.RunWithoutSuccessfulExit(o, "pause", testContainerName())
+			gomega.Expect(inspect.Container(o, testContainerName()).State.Status).To(gomega.Equal("exited"))
+		})
+
+		ginkgo.It("should not pause a paused container", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "pause", testContainerName())
+			command.RunWithoutSuccessfulExit(o, "pause", testContainerName())
+			gomega.Expect(inspect.Container(o, testContainerName()).State.Status).To(gomega.Equal("paused"))
+		})
+	})
+}
@@ -12,32 +12,30 @@ import (
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/fnet"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Port tests listing port mappings or a specific mapping for a container.
 func Port(o *option.Option) {
-	ginkgo.Describe("list port mapping", func() {
+	ginkgo.Describe("list port mapping", labelsOf("Port"), func() {
 		const containerPort = 4567
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should output port mappings for a container", func() {
 			hostPort := fnet.GetFreePort()
-			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName, localImages[defaultImage])
+			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName(), localImages[defaultImage])
 
-			output := command.StdoutStr(o, "port", testContainerName)
+			output := command.StdoutStr(o, "port", testContainerName())
 			gomega.Expect(output).Should(gomega.Equal(fmt.Sprintf("%d/tcp -> 0.0.0.0:%d", containerPort, hostPort)))
 		})
 
 		ginkgo.It("should output the host port according to container port", func() {
 			hostPort := fnet.GetFreePort()
-			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName, localImages[defaultImage])
+			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName(), localImages[defaultImage])
 
-			output := command.StdoutStr(o, "port", testContainerName, fmt.Sprintf("%d/tcp", containerPort))
+			output := command.StdoutStr(o, "port", testContainerName(), fmt.Sprintf("%d/tcp", containerPort))
 			gomega.Expect(output).Should(gomega.Equal(fmt.Sprintf("0.0.0.0:%d", hostPort)))
 		})
 
@@ -48,26 +46,26 @@ func Port(o *option.Option) {
 				"-p",
 				fmt.Sprintf("%d:%d/udp", hostPort, containerPort),
 				"--name",
-				testContainerName,
+				testContainerName(),
 				localImages[defaultImage],
 			)
 
-			command.RunWithoutSuccessfulExit(o, "port", testContainerName, fmt.Sprintf("%d/tcp", containerPort))
+			command.RunWithoutSuccessfulExit(o, "port", testContainerName(), fmt.Sprintf("%d/tcp", containerPort))
 		})
 
 		ginkgo.It("should still output the host port according to container port when no protocol is specified", func() {
 			hostPort := fnet.GetFreePort()
-			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName, localImages[defaultImage])
+			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName(), localImages[defaultImage])
 
-			output := command.StdoutStr(o, "port", testContainerName, fmt.Sprint(containerPort))
+			output := command.StdoutStr(o, "port", testContainerName(), fmt.Sprint(containerPort))
 			gomega.Expect(output).Should(gomega.Equal(fmt.Sprintf("0.0.0.0:%d", hostPort)))
 		})
 
 		ginkgo.It("should have error if trying to print container port which is not published to any host port", func() {
 			hostPort := fnet.GetFreePort()
-			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName, localImages[defaultImage])
+			command.Run(o, "run", "-p", fmt.Sprintf("%d:%d", hostPort, containerPort), "--name", testContainerName(), localImages[defaultImage])
 
-			command.RunWithoutSuccessfulExit(o, "port", testContainerName, "111/tcp")
+			command.RunWithoutSuccessfulExit(o, "port", testContainerName(), "111/tcp")
 		})
 	})
 }
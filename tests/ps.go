@@ -11,19 +11,27 @@ import (
 	"github.com/onsi/gomega"
 
 	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/ffs"
 	"github.com/runfinch/common-tests/option"
 )
 
+// psNetwork is the network created by the Ps specs.
+// A fixed name is used because the filters are built while the spec tree is constructed.
+const psNetwork = "ps-test-network"
+
+// psVolumeMountPoint is where the directory bind-mounted by the Ps specs is mounted in the container.
+const psVolumeMountPoint = "/mnt/ps-volume"
+
 // Ps tests functionality of `ps` command.
 func Ps(o *option.Option) {
 	sha256RegexTruncated := `^[a-f0-9]{12}$`
 	sha256RegexFull := `^[a-f0-9]{64}$`
 
 	containerNames := []string{"ctr_1", "ctr_2"}
-	ginkgo.Describe("Ps command", func() {
+	ginkgo.Describe("Ps command", labelsOf("Ps"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
-			command.Run(o, "network", "create", testNetwork)
+			command.Run(o, "network", "create", psNetwork)
 			command.Run(o, "run", "-d",
 				"--name", containerNames[0],
 				localImages[defaultImage])
@@ -107,20 +115,21 @@ func Ps(o *option.Option) {
 		}
 	})
 
-	ginkgo.Describe("Ps command", func() {
-		pwd, _ := os.Getwd()
+	ginkgo.Describe("Ps command", labelsOf("Ps"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
-			command.Run(o, "network", "create", testNetwork)
+			command.Run(o, "network", "create", psNetwork)
+			volumeDir := ffs.CreateTempDir("finch-test-ps")
+			ginkgo.DeferCleanup(os.RemoveAll, volumeDir)
 			command.Run(o, "run", "-d",
 				"--name", containerNames[0],
 				"--label", "color=red",
-				"-v", fmt.Sprintf("%s:%s", pwd, pwd),
-				"-w", pwd,
+				"-v", fmt.Sprintf("%s:%s", volumeDir, psVolumeMountPoint),
+				"-w", psVolumeMountPoint,
 				localImages[defaultImage])
 			command.Run(o, "run", "-d",
 				"--label", "color=green",
-				"--network", testNetwork,
+				"--network", psNetwork,
 				"-p", "8081:80",
 				"--name", containerNames[1],
 				localImages[defaultImage], "sleep", "infinity")
@@ -135,20 +144,22 @@ func Ps(o *option.Option) {
 		})
 	})
 
-	ginkgo.Describe("Ps command", func() {
-		pwd, _ := os.Getwd()
+	ginkgo.Describe("Ps command", labelsOf("Ps"), ginkgo.Serial, func() {
+		var volumeDir string
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
-			command.Run(o, "network", "create", testNetwork)
+			command.Run(o, "network", "create", psNetwork)
+			volumeDir = ffs.CreateTempDir("finch-test-ps")
+			ginkgo.DeferCleanup(os.RemoveAll, volumeDir)
 			command.Run(o, "run", "-d",
 				"--name", containerNames[0],
 				"--label", "color=red",
-				"-v", fmt.Sprintf("%s:%s", pwd, pwd),
-				"-w", pwd,
+				"-v", fmt.Sprintf("%s:%s", volumeDir, psVolumeMountPoint),
+				"-w", psVolumeMountPoint,
 				localImages[defaultImage])
 			command.Run(o, "run", "-d",
 				"--label", "color=green",
-				"--network", testNetwork,
+				"--network", psNetwork,
 				"-p", "8081:80",
 				"--name", containerNames[1],
 				localImages[defaultImage], "sleep", "infinity")
@@ -194,11 +205,11 @@ func Ps(o *option.Option) {
 				expectedOutput: []string{containerNames[1]},
 			},
 			{
-				filter:         fmt.Sprintf("volume=%s", pwd),
+				filter:         fmt.Sprintf("volume=%s", psVolumeMountPoint),
 				expectedOutput: []string{containerNames[0]},
 			},
 			{
-				filter:         fmt.Sprintf("network=%s", testNetwork),
+				filter:         fmt.Sprintf("network=%s", psNetwork),
 				expectedOutput: []string{containerNames[1]},
 			},
 		}
@@ -209,5 +220,11 @@ func Ps(o *option.Option) {
 				gomega.Expect(output).Should(gomega.ContainElements(test.expectedOutput))
 			})
 		}
+
+		// The source is only known when the spec runs, and the subject may see it at a different path.
+		ginkgo.It(" should list container with filter volume=<source of the bind mount>", func() {
+			output := command.StdoutAsLines(o, "ps", "-a", "--format", "{{.Names}}", "--filter", "volume="+o.SubjectPath(volumeDir))
+			gomega.Expect(output).Should(gomega.ContainElement(containerNames[0]))
+		})
 	})
 }
@@ -12,7 +12,7 @@ import (
 
 // Pull tests pulling a container image.
 func Pull(o *option.Option) {
-	ginkgo.Describe("pull a container image", func() {
+	ginkgo.Describe("pull a container image", labelsOf("Pull"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveImages(o)
 		})
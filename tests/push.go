@@ -12,15 +12,14 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/ffs"
-	"github.com/runfinch/common-tests/fnet"
 	"github.com/runfinch/common-tests/option"
 )
 
 // Push tests pushing an image to a registry.
 func Push(o *option.Option) {
-	ginkgo.Describe("Push a container image to registry", func() {
+	ginkgo.Describe("Push a container image to registry", labelsOf("Push"), ginkgo.Serial, func() {
 		var buildContext string
-		var port int
+		var registry string
 
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
@@ -28,8 +27,7 @@ func Push(o *option.Option) {
 		CMD ["echo", "bar"]
 			`, localImages[defaultImage]))
 			ginkgo.DeferCleanup(os.RemoveAll, buildContext)
-			port = fnet.GetFreePort()
-			command.Run(o, "run", "-dp", fmt.Sprintf("%d:5000", port), "--name", "registry", registryImage)
+			registry = startRegistry(o, "")
 		})
 
 		ginkgo.AfterEach(func() {
@@ -38,14 +36,14 @@ func Push(o *option.Option) {
 
 		ginkgo.Context("Test push command without any flag", func() {
 			ginkgo.It("should push an image with a valid tag to registry", func() {
-				tag := fmt.Sprintf(`localhost:%d/test-push:tag`, port)
+				tag := fmt.Sprintf(`%s/test-push:tag`, registry)
 				command.Run(o, "build", "-t", tag, buildContext)
 				command.Run(o, "push", tag)
 				command.Run(o, "pull", tag)
 			})
 
 			ginkgo.It("should return an error when pushing a nonexistent tag", func() {
-				nonexistentTag := fmt.Sprintf(`localhost:%d/nonexistent:tag`, port)
+				nonexistentTag := fmt.Sprintf(`%s/nonexistent:tag`, registry)
 				stderr := command.RunWithoutSuccessfulExit(o, "push", nonexistentTag).Err.Contents()
 				gomega.Expect(stderr).To(gomega.ContainSubstring("not found"))
 			})
@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// Rename tests the "rename" command that renames a container.
+func Rename(o *option.Option) {
+	ginkgo.Describe("rename a container", labelsOf("Rename"), func() {
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o)
+		})
+
+		ginkgo.It("should rename a running container", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			id := inspect.Container(o, testContainerName()).ID
+			command.Run(o, "rename", testContainerName(), testContainerName2())
+
+			gomega.Expect(command.StdoutAsLines(o, "ps", "--filter", fmt.Sprintf("name=%s", testContainerName2()), "--format", "{{.Names}}")).
+				To(gomega.Equal([]string{testContainerName2()}))
+			gomega.Expect(containerShouldNotExist(o, testContainerName())).To(gomega.Succeed())
+			c := inspect.Container(o, testContainerName2())
+			gomega.Expect(c.Name).To(gomega.Equal(testContainerName2()))
+			gomega.Expect(c.ID).To(gomega.Equal(id))
+			gomega.Expect(c.State.Running).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should rename a stopped container", func() {
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			command.Run(o, "rename", testContainerName(), testContainerName2())
+			gomega.Expect(command.StdoutAsLines(o, "ps", "-a", "--filter", fmt.Sprintf("name=%s", testContainerName2()), "--format", "{{.Names}}")).
+				To(gomega.Equal([]string{testContainerName2()}))
+			gomega.Expect(inspect.Container(o, testContainerName2()).State.Status).To(gomega.Equal("exited"))
+		})
+
+		ginkgo.It("should not rename a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "rename", nonexistentContainerName, testContainerName2())
+		})
+
+		ginkgo.It("should not rename a container to the name of another container", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "run", "-d", "--name", testContainerName2(), localImages[defaultImage], "sleep", "infinity")
+			command.RunWithoutSuccessfulExit(o, "rename", testContainerName(), testContainerName2())
+			containerShouldBeRunning(o, testContainerName(), testContainerName2())
+		})
+	})
+}
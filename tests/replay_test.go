@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/ffs"
+	"github.com/runfinch/common-tests/option"
+)
+
+var _ = ginkgo.Describe("replaying a transcript", func() {
+	// buildSpec runs what a spec that builds an image runs, including SetupLocalRegistry, which publishes the local registry
+	// on a free port, and returns the output of the build, whose context is in a new temporary directory.
+	buildSpec := func(o *option.Option) string {
+		SetupLocalRegistry(o)
+		defer CleanupLocalRegistry(o)
+		buildContext := ffs.CreateBuildContext(fmt.Sprintf("FROM %s\n", localImages[defaultImage]))
+		ginkgo.DeferCleanup(os.RemoveAll, buildContext)
+		return command.StdoutStr(o, "build", "-q", "-t", "replayed", buildContext)
+	}
+
+	ginkgo.It("should replay a recorded spec whose ports and temporary directories change from run to run", func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		path := filepath.Join(dir, "transcript.jsonl")
+		recorder := filepath.Join(dir, "record.yaml")
+		gomega.Expect(os.WriteFile(recorder, []byte(`
+rules:
+  - args: ^build
+    stdout: "sha256:built\n"
+default:
+  exitCode: 0
+`), 0o600)).Should(gomega.Succeed())
+		o, err := option.New([]string{fakeSubject, recorder}, option.WithTranscript(path))
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(buildSpec(o)).Should(gomega.Equal("sha256:built"))
+
+		// Without any rule, the fake subject fails every invocation that is not found in the transcript.
+		replayer := filepath.Join(dir, "replay.yaml")
+		scenario := fmt.Sprintf("log: %s\nreplay: %s\n", filepath.Join(dir, "invocations.jsonl"), path)
+		gomega.Expect(os.WriteFile(replayer, []byte(scenario), 0o600)).Should(gomega.Succeed())
+		replay, err := option.New([]string{fakeSubject, replayer})
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Expect(buildSpec(replay)).Should(gomega.Equal("sha256:built"))
+	})
+})
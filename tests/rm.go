@@ -11,41 +11,39 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Rm tests removing a container.
 func Rm(o *option.Option) {
-	ginkgo.Describe("remove a container", func() {
+	ginkgo.Describe("remove a container", labelsOf("Rm"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should remove the container when it is not running", func() {
-			command.Run(o, "run", "--name", testContainerName, localImages[defaultImage])
-			containerShouldExist(o, testContainerName)
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			containerShouldExist(o, testContainerName())
 
-			command.Run(o, "rm", testContainerName)
-			err := containerShouldNotExist(o, testContainerName)
+			command.Run(o, "rm", testContainerName())
+			err := containerShouldNotExist(o, testContainerName())
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
 		ginkgo.Context("when the container is running", func() {
 			ginkgo.BeforeEach(func() {
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 			})
 
 			ginkgo.It("should not be able to remove the container without -f/--force flag", func() {
-				command.RunWithoutSuccessfulExit(o, "rm", testContainerName)
-				containerShouldExist(o, testContainerName)
+				command.RunWithoutSuccessfulExit(o, "rm", testContainerName())
+				containerShouldExist(o, testContainerName())
 			})
 
 			for _, force := range []string{"-f", "--force"} {
 				ginkgo.It(fmt.Sprintf("should be able to remove the container with %s flag", force), func() {
-					command.Run(o, "rm", force, testContainerName)
-					err := containerShouldNotExist(o, testContainerName)
+					command.Run(o, "rm", force, testContainerName())
+					err := containerShouldNotExist(o, testContainerName())
 					gomega.Expect(err).NotTo(gomega.HaveOccurred())
 				})
 			}
@@ -55,13 +53,13 @@ func Rm(o *option.Option) {
 			for _, volumes := range []string{"-v", "--volumes"} {
 				ginkgo.It(fmt.Sprintf("with %s flag, should remove the container and the anonymous volume used by the container", volumes),
 					func() {
-						command.Run(o, "run", "-v", "/usr/share", "--name", testContainerName, localImages[defaultImage])
-						anonymousVolume := command.StdoutStr(o, "inspect", testContainerName,
+						command.Run(o, "run", "-v", "/usr/share", "--name", testContainerName(), localImages[defaultImage])
+						anonymousVolume := command.StdoutStr(o, "inspect", testContainerName(),
 							"--format", "{{range .Mounts}}{{.Name}}{{end}}")
-						containerShouldExist(o, testContainerName)
+						containerShouldExist(o, testContainerName())
 						volumeShouldExist(o, anonymousVolume)
-						command.Run(o, "rm", volumes, testContainerName)
-						err := containerShouldNotExist(o, testContainerName)
+						command.Run(o, "rm", volumes, testContainerName())
+						err := containerShouldNotExist(o, testContainerName())
 						gomega.Expect(err).NotTo(gomega.HaveOccurred())
 						volumeShouldNotExist(o, anonymousVolume)
 					},
@@ -69,11 +67,11 @@ func Rm(o *option.Option) {
 
 				ginkgo.It(fmt.Sprintf("with %s flag, should remove the container but can't remove the named volume used by container", volumes),
 					func() {
-						command.Run(o, "run", "-v", "foo:/usr/share", "--name", testContainerName, localImages[defaultImage])
+						command.Run(o, "run", "-v", "foo:/usr/share", "--name", testContainerName(), localImages[defaultImage])
 						volumeShouldExist(o, "foo")
 
-						command.Run(o, "rm", volumes, testContainerName)
-						err := containerShouldNotExist(o, testContainerName)
+						command.Run(o, "rm", volumes, testContainerName())
+						err := containerShouldNotExist(o, testContainerName())
 						gomega.Expect(err).NotTo(gomega.HaveOccurred())
 						volumeShouldExist(o, "foo")
 					},
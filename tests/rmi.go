@@ -14,7 +14,7 @@ import (
 
 // Rmi tests removing a container image.
 func Rmi(o *option.Option) {
-	ginkgo.Describe("remove a container image", func() {
+	ginkgo.Describe("remove a container image", labelsOf("Rmi"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 		})
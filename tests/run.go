@@ -10,7 +10,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 
@@ -22,28 +21,28 @@ import (
 	"github.com/runfinch/common-tests/ffs"
 	"github.com/runfinch/common-tests/fnet"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // RunOption is the custom option to run tests in run.go.
 type RunOption struct {
 	// BaseOpt instructs how to run the test subject.
 	BaseOpt *option.Option
-	// CGMode is the cgroup mode that the host uses.
+	// CGMode is the cgroup mode that the host uses. Use DetectCGMode to detect it.
 	CGMode CGMode
+	// detectCGMode detects the cgroup mode with DetectCGMode when the specs run instead of using CGMode.
+	detectCGMode bool
 	// DefaultHostGatewayIP is the IP that the test subject will resolve special IP `host-gateway` to.
+	// If it's empty, the IP is expected to be the one detected with DetectHostGatewayIP.
 	DefaultHostGatewayIP string
 }
 
 // Run tests running a container image.
 // TODO: test cases for bind propagation options.
 func Run(o *RunOption) {
-	ginkgo.Describe("Run a container image", func() {
+	ginkgo.Describe("Run a container image", labelsOf("Run"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o.BaseOpt)
-		})
-
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o.BaseOpt)
+			testutil.UseSpecNamespace(o.BaseOpt)
 		})
 
 		ginkgo.When("running a container that echos dummy output", func() {
@@ -53,16 +52,16 @@ func Run(o *RunOption) {
 			`, localImages[defaultImage])
 				buildContext := ffs.CreateBuildContext(dockerfile)
 				ginkgo.DeferCleanup(os.RemoveAll, buildContext)
-				command.Run(o.BaseOpt, "build", "-q", "-t", testImageName, buildContext)
+				command.Run(o.BaseOpt, "build", "-q", "-t", testImageName(), buildContext)
 			})
 
 			ginkgo.It("should echo dummy output", func() {
-				output := command.StdoutStr(o.BaseOpt, "run", testImageName)
+				output := command.StdoutStr(o.BaseOpt, "run", testImageName())
 				gomega.Expect(output).Should(gomega.Equal("finch-test-dummy-output"))
 			})
 
 			ginkgo.It("should not echo dummy output if running with -d flag", func() {
-				output := command.Stdout(o.BaseOpt, "run", "-d", testImageName)
+				output := command.Stdout(o.BaseOpt, "run", "-d", testImageName())
 				gomega.Expect(output).ShouldNot(gomega.ContainSubstring("finch-test-dummy-output"))
 			})
 		})
@@ -70,41 +69,37 @@ func Run(o *RunOption) {
 		ginkgo.It("container should exit with exit code in case of an error", func() {
 			exitCode := 10
 			cmd := fmt.Sprintf("exit %d", exitCode)
-			session := command.RunWithoutSuccessfulExit(o.BaseOpt, "run", "--rm", "--name", testContainerName,
+			session := command.RunWithoutSuccessfulExit(o.BaseOpt, "run", "--rm", "--name", testContainerName(),
 				localImages[defaultImage], "sh", "-c", cmd)
 			gomega.Expect(session.ExitCode()).To(gomega.Equal(exitCode))
 		})
 
 		ginkgo.It("with --rm flag, container should be removed when it exits", func() {
-			command.Run(o.BaseOpt, "run", "--rm", "--name", testContainerName, localImages[defaultImage])
-			err := containerShouldNotExist(o.BaseOpt, testContainerName)
+			command.Run(o.BaseOpt, "run", "--rm", "--name", testContainerName(), localImages[defaultImage])
+			err := containerShouldNotExist(o.BaseOpt, testContainerName())
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
 		ginkgo.When("running a container with metadata related flags", func() {
 			for _, label := range []string{"-l", "--label"} {
 				ginkgo.It(fmt.Sprintf("should set meta data on a container with %s flag", label), func() {
-					command.Run(o.BaseOpt, "run", "--name", testContainerName, label, "testKey=testValue", localImages[defaultImage])
-					gomega.Expect(command.StdoutStr(o.BaseOpt, "inspect", testContainerName,
+					command.Run(o.BaseOpt, "run", "--name", testContainerName(), label, "testKey=testValue", localImages[defaultImage])
+					gomega.Expect(command.StdoutStr(o.BaseOpt, "inspect", testContainerName(),
 						"--format", "{{.Config.Labels.testKey}}")).To(gomega.Equal("testValue"))
 				})
 			}
 
 			ginkgo.It("should write the container ID to file with --cidfile flag", func() {
-				dir := ffs.CreateTempDir("finch-test-cid")
-				ginkgo.DeferCleanup(os.RemoveAll, dir)
-				path := filepath.Join(dir, "test.cid")
+				path := filepath.Join(subjectDir(o.BaseOpt, "finch-test-cid"), "test.cid")
 				containerID := command.StdoutStr(o.BaseOpt, "run", "-d", "--cidfile", path, localImages[defaultImage])
-				output, err := os.ReadFile(filepath.Clean(path))
-				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
-				gomega.Expect(strings.TrimSpace(string(output))).Should(gomega.Equal(containerID))
+				gomega.Expect(strings.TrimSpace(subjectFile(o.BaseOpt, path))).Should(gomega.Equal(containerID))
 			})
 
 			ginkgo.It("should read labels from file with --label-file flag", func() {
 				path := ffs.CreateTempFile("label-file", "key=value")
 				ginkgo.DeferCleanup(os.RemoveAll, filepath.Dir(path))
-				command.Run(o.BaseOpt, "run", "--name", testContainerName, "--label-file", path, localImages[defaultImage])
-				gomega.Expect(command.StdoutStr(o.BaseOpt, "inspect", testContainerName,
+				command.Run(o.BaseOpt, "run", "--name", testContainerName(), "--label-file", path, localImages[defaultImage])
+				gomega.Expect(command.StdoutStr(o.BaseOpt, "inspect", testContainerName(),
 					"--format", "{{.Config.Labels.key}}")).To(gomega.Equal("value"))
 			})
 		})
@@ -119,9 +114,9 @@ func Run(o *RunOption) {
 				defer func() {
 					gomega.Expect(os.RemoveAll(buildContext)).To(gomega.Succeed())
 				}()
-				command.Run(o.BaseOpt, "build", "-q", "-t", testImageName, buildContext)
+				command.Run(o.BaseOpt, "build", "-q", "-t", testImageName(), buildContext)
 
-				envOutput := command.Stdout(o.BaseOpt, "run", "--rm", "--entrypoint", "time", testImageName, "echo", "blah")
+				envOutput := command.Stdout(o.BaseOpt, "run", "--rm", "--entrypoint", "time", testImageName(), "echo", "blah")
 				gomega.Expect(envOutput).NotTo(gomega.ContainSubstring("foo"))
 				gomega.Expect(envOutput).NotTo(gomega.ContainSubstring("bar"))
 				gomega.Expect(envOutput).To(gomega.ContainSubstring("blah"))
@@ -194,39 +189,43 @@ func Run(o *RunOption) {
 			})
 		})
 
-		ginkgo.When("running an image with --pull flag", func() {
+		ginkgo.When("running an image with --pull flag", ginkgo.Serial, func() {
+			ginkgo.BeforeEach(func() {
+				// The image is shared by the other specs, so it's only removed when they are not running.
+				command.RemoveImages(o.BaseOpt)
+			})
+
 			ginkgo.It("should have an error if set --pull=never and the image doesn't exist", func() {
 				command.RunWithoutSuccessfulExit(o.BaseOpt, "run", "--pull", "never", localImages[defaultImage])
 				imageShouldNotExist(o.BaseOpt, localImages[defaultImage])
 			})
 
 			ginkgo.It("should be able to run the container if set --pull=never and the image exists", func() {
-				const containerName = "test-container"
 				pullImage(o.BaseOpt, localImages[defaultImage])
-				command.Run(o.BaseOpt, "run", "--name", containerName, "--pull", "never", localImages[defaultImage])
-				containerShouldExist(o.BaseOpt, containerName)
+				command.Run(o.BaseOpt, "run", "--name", testContainerName(), "--pull", "never", localImages[defaultImage])
+				containerShouldExist(o.BaseOpt, testContainerName())
 			})
 
 			ginkgo.It("should be able to run the container if set --pull=missing and the image doesn't exist", func() {
-				command.Run(o.BaseOpt, "run", "--name", testContainerName, "--pull", "missing", localImages[defaultImage])
-				containerShouldExist(o.BaseOpt, testContainerName)
+				command.Run(o.BaseOpt, "run", "--name", testContainerName(), "--pull", "missing", localImages[defaultImage])
+				containerShouldExist(o.BaseOpt, testContainerName())
 				imageShouldExist(o.BaseOpt, localImages[defaultImage])
 			})
 
 			ginkgo.It("should be able to run the container if set --pull=missing and the image exists", func() {
 				pullImage(o.BaseOpt, localImages[defaultImage])
-				command.Run(o.BaseOpt, "run", "--name", testContainerName, "--pull", "missing", localImages[defaultImage])
-				containerShouldExist(o.BaseOpt, testContainerName)
+				command.Run(o.BaseOpt, "run", "--name", testContainerName(), "--pull", "missing", localImages[defaultImage])
+				containerShouldExist(o.BaseOpt, testContainerName())
 			})
 
 			ginkgo.It("should be able to run the container if set --pull=always and the image doesn't exist", func() {
-				command.Run(o.BaseOpt, "run", "--name", testContainerName, "--pull", "always", localImages[defaultImage])
-				containerShouldExist(o.BaseOpt, testContainerName)
+				command.Run(o.BaseOpt, "run", "--name", testContainerName(), "--pull", "always", localImages[defaultImage])
+				containerShouldExist(o.BaseOpt, testContainerName())
 				imageShouldExist(o.BaseOpt, localImages[defaultImage])
 			})
 			ginkgo.It("should be able to run the container if set --pull=always and the image exists", func() {
 				pullImage(o.BaseOpt, localImages[defaultImage])
-				command.Run(o.BaseOpt, "run", "--name", testContainerName, "--pull", "always", localImages[defaultImage])
+				command.Run(o.BaseOpt, "run", "--name", testContainerName(), "--pull", "always", localImages[defaultImage])
 			})
 		})
 
@@ -239,6 +238,24 @@ func Run(o *RunOption) {
 			})
 		}
 
+		for _, tty := range []string{"-it", "--interactive --tty"} {
+			ginkgo.It(fmt.Sprintf("should allocate a TTY for an interactive shell with %s flags", tty), ginkgo.Label(LabelNeedsTerminal), func() {
+				args := append(append([]string{"run"}, strings.Fields(tty)...), "--name", testContainerName(), localImages[defaultImage], "sh")
+				terminal := command.StartInTerminal(o.BaseOpt, args...)
+				shouldRespondInTerminal(terminal)
+				terminal.SendLine("exit 0")
+				gomega.Expect(terminal.Wait(terminalTimeout).ExitCode()).To(gomega.Equal(0))
+			})
+		}
+
+		ginkgo.It("should detach from an interactive container with the keys specified by --detach-keys flag", ginkgo.Label(LabelNeedsTerminal),
+			func() {
+				terminal := command.StartInTerminal(o.BaseOpt, "run", "-it", "--detach-keys", customDetachKeys,
+					"--name", testContainerName(), localImages[defaultImage], "sh")
+				shouldRespondInTerminal(terminal)
+				shouldDetachInTerminal(o.BaseOpt, terminal, customDetachKeys)
+			})
+
 		ginkgo.It("should stop running container within specified time by --stop-timeout flag", func() {
 			// With PID=1, `sleep infinity` does not exit due to receiving a SIGTERM, which is sent by the stop command.
 			// Ref. https://superuser.com/a/1299463/730265
@@ -247,19 +264,19 @@ func Run(o *RunOption) {
 				"run",
 				"-d",
 				"--name",
-				testContainerName,
+				testContainerName(),
 				"--stop-timeout",
 				"1",
 				localImages[defaultImage],
 				"sleep",
 				"infinity",
 			)
-			gomega.Expect(command.StdoutStr(o.BaseOpt, "exec", testContainerName, "echo", "foo")).To(gomega.Equal("foo"))
+			gomega.Expect(command.StdoutStr(o.BaseOpt, "exec", testContainerName(), "echo", "foo")).To(gomega.Equal("foo"))
 			startTime := time.Now()
-			command.Run(o.BaseOpt, "stop", testContainerName)
+			command.Run(o.BaseOpt, "stop", testContainerName())
 			// assert the container to be stopped within 1.5 seconds
 			gomega.Expect(time.Since(startTime)).To(gomega.BeNumerically("~", 0*time.Millisecond, 2500*time.Millisecond))
-			command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName, "echo", "foo")
+			command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName(), "echo", "foo")
 		})
 
 		ginkgo.It("should immediately stop the container with --stop-signal=SIGKILL", func() {
@@ -269,43 +286,43 @@ func Run(o *RunOption) {
 				"run",
 				"-d",
 				"--name",
-				testContainerName,
+				testContainerName(),
 				"--stop-signal",
 				"SIGKILL",
 				localImages[defaultImage],
 				"sleep",
 				"infinity",
 			)
-			containerShouldBeRunning(o.BaseOpt, testContainerName)
+			containerShouldBeRunning(o.BaseOpt, testContainerName())
 			startTime := time.Now()
-			command.Run(o.BaseOpt, "stop", testContainerName)
+			command.Run(o.BaseOpt, "stop", testContainerName())
 			gomega.Expect(time.Since(startTime)).To(gomega.BeNumerically("~", 0*time.Millisecond, 2000*time.Millisecond))
-			status := command.StdoutStr(o.BaseOpt, "inspect", "--format", "{{.State.Status}}", testContainerName)
+			status := command.StdoutStr(o.BaseOpt, "inspect", "--format", "{{.State.Status}}", testContainerName())
 			gomega.Expect(status).Should(gomega.Equal("exited"))
-			command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName, "echo", "foo")
+			command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName(), "echo", "foo")
 		})
 
-		ginkgo.It("should share PID namespace with host with --pid=host", func() {
-			command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName, "--pid=host", localImages[defaultImage], "sleep", "infinity")
-			pid := command.StdoutStr(o.BaseOpt, "inspect", "--format", "{{.State.Pid}}", testContainerName)
-			command.Run(o.BaseOpt, "exec", testContainerName, "sh", "-c", fmt.Sprintf("ps -o pid,comm | grep '%s sleep'", pid))
+		ginkgo.It("should share PID namespace with host with --pid=host", ginkgo.Label(LabelNeedsPrivilege), func() {
+			command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName(), "--pid=host", localImages[defaultImage], "sleep", "infinity")
+			pid := command.StdoutStr(o.BaseOpt, "inspect", "--format", "{{.State.Pid}}", testContainerName())
+			command.Run(o.BaseOpt, "exec", testContainerName(), "sh", "-c", fmt.Sprintf("ps -o pid,comm | grep '%s sleep'", pid))
 		})
 
 		ginkgo.It("should share PID namespace with a container with --pid=container:<container>", func() {
-			command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
-			// We are joining the pid namespace that was "created" by testContainerName,
-			// so the pid=1 process will be the main process of testContainerName, which is `sleep`.
-			command.Run(o.BaseOpt, "exec", testContainerName, "sh", "-c", "ps -o pid,comm | grep '1 sleep'")
+			command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			// We are joining the pid namespace that was "created" by testContainerName(),
+			// so the pid=1 process will be the main process of testContainerName(), which is `sleep`.
+			command.Run(o.BaseOpt, "exec", testContainerName(), "sh", "-c", "ps -o pid,comm | grep '1 sleep'")
 		})
 
 		ginkgo.When("running a container with network related flags", func() {
 			// TODO: add tests for --ip, --mac-address flags
 			for _, network := range []string{"--net", "--network"} {
 				ginkgo.It(fmt.Sprintf("should connect a container to a network with %s flag", network), func() {
-					command.Run(o.BaseOpt, "run", "-d", network, "bridge", "--name", testContainerName,
+					command.Run(o.BaseOpt, "run", "-d", network, "bridge", "--name", testContainerName(),
 						localImages[defaultImage], "sh", "-c", "echo hello | nc -l -p 80")
 					ipAddr := command.StdoutStr(o.BaseOpt, "inspect", "--format",
-						"{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", testContainerName)
+						"{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", testContainerName())
 					output := command.StdoutStr(
 						o.BaseOpt,
 						"run",
@@ -319,20 +336,20 @@ func Run(o *RunOption) {
 				})
 
 				ginkgo.It(fmt.Sprintf("should use the same network with container specified by %s=container:<name>", network), func() {
-					command.Run(o.BaseOpt, "run", "-d", network, "bridge", "--name", testContainerName,
+					command.Run(o.BaseOpt, "run", "-d", network, "bridge", "--name", testContainerName(),
 						localImages[defaultImage], "sh", "-c", "echo hello | nc -l -p 80")
 					ipAddr := command.StdoutStr(o.BaseOpt, "inspect", "--format",
-						"{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", testContainerName)
-					output := command.StdoutStr(o.BaseOpt, "run", fmt.Sprintf("%s=container:%s", network, testContainerName),
+						"{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", testContainerName())
+					output := command.StdoutStr(o.BaseOpt, "run", fmt.Sprintf("%s=container:%s", network, testContainerName()),
 						localImages[defaultImage], "nc", fmt.Sprintf("%s:80", ipAddr))
 					gomega.Expect(output).Should(gomega.Equal("hello"))
 				})
 
 				ginkgo.It(fmt.Sprintf("should use the same network with container specified by %s=container:<id>", network), func() {
-					id := command.StdoutStr(o.BaseOpt, "run", "-d", network, "bridge", "--name", testContainerName,
+					id := command.StdoutStr(o.BaseOpt, "run", "-d", network, "bridge", "--name", testContainerName(),
 						localImages[defaultImage], "sh", "-c", "echo hello | nc -l -p 80")
 					ipAddr := command.StdoutStr(o.BaseOpt, "inspect", "--format",
-						"{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", testContainerName)
+						"{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", testContainerName())
 					output := command.StdoutStr(o.BaseOpt, "run", fmt.Sprintf("%s=container:%s", network, id),
 						localImages[defaultImage], "nc", fmt.Sprintf("%s:80", ipAddr))
 					gomega.Expect(output).Should(gomega.Equal("hello"))
@@ -341,20 +358,20 @@ func Run(o *RunOption) {
 
 			ginkgo.It("should be able to set custom DNS servers with --dns flag", func() {
 				const nameserver = "10.10.10.10"
-				lines := command.StdoutAsLines(o.BaseOpt, "run", "--dns", nameserver, "--name", testContainerName,
+				lines := command.StdoutAsLines(o.BaseOpt, "run", "--dns", nameserver, "--name", testContainerName(),
 					localImages[defaultImage], "cat", "/etc/resolv.conf")
 				gomega.Expect(lines).Should(gomega.ContainElement(fmt.Sprintf("nameserver %s", nameserver)))
 			})
 
 			ginkgo.It("should be able to set custom DNS search domains with --dns-search flag", func() {
-				lines := command.StdoutAsLines(o.BaseOpt, "run", "--dns-search", "test", "--name", testContainerName,
+				lines := command.StdoutAsLines(o.BaseOpt, "run", "--dns-search", "test", "--name", testContainerName(),
 					localImages[defaultImage], "cat", "/etc/resolv.conf")
 				gomega.Expect(lines).Should(gomega.ContainElement("search test"))
 			})
 
 			for _, dnsOption := range []string{"--dns-opt", "--dns-option"} {
 				ginkgo.It(fmt.Sprintf("should be able to set DNS option with %s flag", dnsOption), func() {
-					lines := command.StdoutAsLines(o.BaseOpt, "run", dnsOption, "debug", "--name", testContainerName,
+					lines := command.StdoutAsLines(o.BaseOpt, "run", dnsOption, "debug", "--name", testContainerName(),
 						localImages[defaultImage], "cat", "/etc/resolv.conf")
 					gomega.Expect(lines).Should(gomega.ContainElement("options debug"))
 				})
@@ -393,19 +410,17 @@ func Run(o *RunOption) {
 
 				time.Sleep(5 * time.Second)
 				ginkgo.DeferCleanup(s.Shutdown, ctx)
-				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName, "--add-host", "test-host:host-gateway",
+				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName(), "--add-host", "test-host:host-gateway",
 					localImages[amazonLinux2Image], "sleep", "infinity")
-				mapping := command.StdoutStr(o.BaseOpt, "exec", testContainerName, "cat", "/etc/hosts")
-				gomega.Expect(mapping).Should(gomega.ContainSubstring(o.DefaultHostGatewayIP))
-				gomega.Expect(mapping).Should(gomega.ContainSubstring("test-host"))
-				gomega.Expect(command.StdoutStr(o.BaseOpt, "exec", testContainerName, "curl",
+				mapping := command.StdoutStr(o.BaseOpt, "exec", testContainerName(), "cat", "/etc/hosts")
+				o.hostGatewayShouldBeResolved(mapping, "test-host")
+				gomega.Expect(command.StdoutStr(o.BaseOpt, "exec", testContainerName(), "curl",
 					fmt.Sprintf("test-host:%d", hostPort))).Should(gomega.Equal(response))
-				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName2, "--add-host=test-host:host-gateway",
+				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName2(), "--add-host=test-host:host-gateway",
 					localImages[amazonLinux2Image], "sleep", "infinity")
-				mapping = command.StdoutStr(o.BaseOpt, "exec", testContainerName2, "cat", "/etc/hosts")
-				gomega.Expect(mapping).Should(gomega.ContainSubstring(o.DefaultHostGatewayIP))
-				gomega.Expect(mapping).Should(gomega.ContainSubstring("test-host"))
-				gomega.Expect(command.StdoutStr(o.BaseOpt, "exec", testContainerName2, "curl",
+				mapping = command.StdoutStr(o.BaseOpt, "exec", testContainerName2(), "cat", "/etc/hosts")
+				o.hostGatewayShouldBeResolved(mapping, "test-host")
+				gomega.Expect(command.StdoutStr(o.BaseOpt, "exec", testContainerName2(), "curl",
 					fmt.Sprintf("test-host:%d", hostPort))).Should(gomega.Equal(response))
 			})
 
@@ -433,96 +448,78 @@ func Run(o *RunOption) {
 			)
 			for _, volume := range []string{"-v", "--volume"} {
 				ginkgo.It(fmt.Sprintf("should mount a volume when running a container with %s", volume), func() {
-					command.Run(o.BaseOpt, "run", "--name", testContainerName, volume,
-						fmt.Sprintf("%s:%s", testVolumeName, destDir), localImages[defaultImage], "sh", "-c", "echo foo > /tmp/test.txt")
-					srcDir := command.StdoutStr(o.BaseOpt, "volume", "inspect", testVolumeName, "--format", "{{.Mountpoint}}")
+					command.Run(o.BaseOpt, "run", "--name", testContainerName(), volume,
+						fmt.Sprintf("%s:%s", testVolumeName(), destDir), localImages[defaultImage], "sh", "-c", "echo foo > /tmp/test.txt")
+					srcDir := command.StdoutStr(o.BaseOpt, "volume", "inspect", testVolumeName(), "--format", "{{.Mountpoint}}")
 					expectedMount := []MountJSON{makeMount(volumeType, srcDir, destDir, "", true)}
-					actualMount := getContainerMounts(o.BaseOpt, testContainerName)
+					actualMount := getContainerMounts(o.BaseOpt, testContainerName())
 					verifyMountsInfo(actualMount, expectedMount)
-					output := command.StdoutStr(o.BaseOpt, "run", "-v", fmt.Sprintf("%s:/tmp", testVolumeName), localImages[defaultImage],
+					output := command.StdoutStr(o.BaseOpt, "run", "-v", fmt.Sprintf("%s:/tmp", testVolumeName()), localImages[defaultImage],
 						"cat", "/tmp/test.txt")
 					gomega.Expect(output).Should(gomega.Equal("foo"))
 				})
 
 				ginkgo.It(fmt.Sprintf("should be able to set the volume options with %s testVol:%s:ro", volume, destDir), func() {
-					command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName, volume,
-						fmt.Sprintf("%s:%s:ro", testVolumeName, destDir), localImages[defaultImage], "sleep", "infinity")
-					srcDir := command.StdoutStr(o.BaseOpt, "volume", "inspect", "--format", "{{.Mountpoint}}", testVolumeName)
+					command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName(), volume,
+						fmt.Sprintf("%s:%s:ro", testVolumeName(), destDir), localImages[defaultImage], "sleep", "infinity")
+					srcDir := command.StdoutStr(o.BaseOpt, "volume", "inspect", "--format", "{{.Mountpoint}}", testVolumeName())
 					expectedMount := []MountJSON{makeMount(volumeType, srcDir, destDir, "ro", false)}
-					actualMount := getContainerMounts(o.BaseOpt, testContainerName)
+					actualMount := getContainerMounts(o.BaseOpt, testContainerName())
 					verifyMountsInfo(actualMount, expectedMount)
 					// verify the volume is readonly
-					command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName, "sh", "-c",
+					command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName(), "sh", "-c",
 						fmt.Sprintf("echo foo > %s/test.txt", destDir))
 				})
 			}
 
 			ginkgo.It("should create a tmpfs mount in a container", func() {
-				const tmpfsContainerName = "tmpfs-ctr"
 				command.Run(o.BaseOpt, "run", "-d", "--tmpfs", fmt.Sprintf("%s:size=64m,exec", destDir),
-					"--name", tmpfsContainerName, localImages[defaultImage], "sleep", "infinity")
+					"--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 				expectedMount := []MountJSON{makeMount(tmpfsType, tmpfsType, destDir, "size=64m,exec", true)}
-				actualMount := getContainerMounts(o.BaseOpt, tmpfsContainerName)
+				actualMount := getContainerMounts(o.BaseOpt, testContainerName())
 				verifyMountsInfo(actualMount, expectedMount)
 				// create a file in tmpfs mount and verify it doesn't exist after stopping and restarting it
-				command.Run(o.BaseOpt, "exec", tmpfsContainerName, "sh", "-c", fmt.Sprintf("echo foo > %s/bar.txt", destDir))
-				command.Run(o.BaseOpt, "kill", tmpfsContainerName) // have to use kill to stop the container running with sleep infinity
-				command.Run(o.BaseOpt, "start", tmpfsContainerName)
-				command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", tmpfsContainerName, "sh", "-c", fmt.Sprintf("cat %s/bar.txt", destDir))
+				command.Run(o.BaseOpt, "exec", testContainerName(), "sh", "-c", fmt.Sprintf("echo foo > %s/bar.txt", destDir))
+				command.Run(o.BaseOpt, "kill", testContainerName()) // have to use kill to stop the container running with sleep infinity
+				command.Run(o.BaseOpt, "start", testContainerName())
+				command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName(), "sh", "-c", fmt.Sprintf("cat %s/bar.txt", destDir))
 			})
 
 			ginkgo.It("should create a bind mount in a container", func() {
-				var err error
 				file := ffs.CreateTempFile("bar.txt", "foo")
 				fileDir := filepath.Dir(file)
 				ginkgo.DeferCleanup(os.RemoveAll, fileDir)
-				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName, "--mount",
+				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName(), "--mount",
 					fmt.Sprintf("type=bind,source=%s,target=%s", fileDir, destDir),
 					localImages[defaultImage], "sleep", "infinity")
 
-				if runtime.GOOS == "windows" {
-					fileDir, err = getWslPath(fileDir)
-					gomega.Expect(err).Should(gomega.BeNil())
-				}
-				expectedMount := []MountJSON{makeMount(bindType, fileDir, destDir, "", true)}
-				actualMount := getContainerMounts(o.BaseOpt, testContainerName)
+				expectedMount := []MountJSON{makeMount(bindType, o.BaseOpt.SubjectPath(fileDir), destDir, "", true)}
+				actualMount := getContainerMounts(o.BaseOpt, testContainerName())
 				verifyMountsInfo(actualMount, expectedMount)
-				output := command.StdoutStr(o.BaseOpt, "exec", testContainerName, "cat", fmt.Sprintf("%s/bar.txt", destDir))
+				output := command.StdoutStr(o.BaseOpt, "exec", testContainerName(), "cat", fmt.Sprintf("%s/bar.txt", destDir))
 				gomega.Expect(output).Should(gomega.Equal("foo"))
 			})
 
 			ginkgo.It("should set the bind mount as readonly with --mount <src>=/src,<target>=/target,ro", func() {
-				var err error
 				file := ffs.CreateTempFile("bar.txt", "foo")
 				fileDir := filepath.Dir(file)
 				ginkgo.DeferCleanup(os.RemoveAll, fileDir)
 				cmd := []byte(fmt.Sprintf("echo hello > %s/world.txt", destDir))
 				// verify the bind mount is readonly by piping the command of creating a file in the interactive mode to the container
-				command.New(o.BaseOpt, "run", "-i", "--name", testContainerName, "--mount",
+				command.New(o.BaseOpt, "run", "-i", "--name", testContainerName(), "--mount",
 					fmt.Sprintf("type=bind,source=%s,target=%s,ro", fileDir, destDir),
 					localImages[defaultImage]).WithStdin(gbytes.BufferWithBytes(cmd)).WithoutSuccessfulExit().Run()
 
-				if runtime.GOOS == "windows" {
-					fileDir, err = getWslPath(fileDir)
-					gomega.Expect(err).Should(gomega.BeNil())
-				}
-				expectedMount := []MountJSON{makeMount(bindType, fileDir, destDir, "ro", false)}
-				actualMount := getContainerMounts(o.BaseOpt, testContainerName)
+				expectedMount := []MountJSON{makeMount(bindType, o.BaseOpt.SubjectPath(fileDir), destDir, "ro", false)}
+				actualMount := getContainerMounts(o.BaseOpt, testContainerName())
 				verifyMountsInfo(actualMount, expectedMount)
 			})
 
 			ginkgo.It("should create nested bind mounts within a container", func() {
-				var err error
 				// Create the nested directory on the host
-				containerOuterDir := ffs.CreateFilePathInHome("outer")
+				containerOuterDir := o.BaseOpt.SubjectPath(ffs.CreateFilePathInHome("outer"))
 				nestedHostDir := ffs.CreateNestedDir(filepath.Join("outer", "nested"))
-				nestedContainerDir := nestedHostDir
-				if runtime.GOOS == "windows" {
-					nestedContainerDir, err = getWslPath(nestedHostDir)
-					gomega.Expect(err).Should(gomega.BeNil())
-					containerOuterDir, err = getWslPath(containerOuterDir)
-					gomega.Expect(err).Should(gomega.BeNil())
-				}
+				nestedContainerDir := o.BaseOpt.SubjectPath(nestedHostDir)
 				defer ffs.DeleteDirectory(nestedHostDir)
 
 				// Directory on host to be mounted at hostDirectory in container
@@ -533,14 +530,14 @@ func Run(o *RunOption) {
 				ffs.WriteFile(nestedFilePath, "test")
 
 				// Mount nested directory first followed by parent directory
-				output := command.StdoutStr(o.BaseOpt, "run", "--rm", "--name", testContainerName,
+				output := command.StdoutStr(o.BaseOpt, "run", "--rm", "--name", testContainerName(),
 					"-v", nestedHostDir+":"+nestedContainerDir,
 					"-v", tempDir+":"+containerOuterDir,
 					localImages[defaultImage], "sh", "-c", "ls "+nestedContainerDir)
 				gomega.Expect(output).Should(gomega.ContainSubstring("file1.txt"))
 
 				// Mount parent directory first followed by nested
-				output = command.StdoutStr(o.BaseOpt, "run", "--rm", "--name", testContainerName2,
+				output = command.StdoutStr(o.BaseOpt, "run", "--rm", "--name", testContainerName2(),
 					"-v", tempDir+":"+containerOuterDir,
 					"-v", nestedHostDir+":"+nestedContainerDir,
 					localImages[defaultImage], "sh", "-c", "ls "+nestedContainerDir)
@@ -549,33 +546,33 @@ func Run(o *RunOption) {
 
 			ginkgo.It("should create a tmpfs mount using --mount type=tmpfs flag", func() {
 				tmpfsDir := "/tmpfsDir"
-				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName, "--mount",
+				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName(), "--mount",
 					fmt.Sprintf("type=tmpfs,destination=%s,tmpfs-mode=1770,tmpfs-size=64m", tmpfsDir),
 					localImages[defaultImage], "sleep", "infinity")
 				expectedMount := []MountJSON{makeMount(tmpfsType, tmpfsType, tmpfsDir, "mode=1770,size=64m", true)}
-				actualMount := getContainerMounts(o.BaseOpt, testContainerName)
+				actualMount := getContainerMounts(o.BaseOpt, testContainerName())
 				verifyMountsInfo(actualMount, expectedMount)
 				// create a file in tmpfs mount and verify it doesn't exist after stopping and restarting it
-				command.Run(o.BaseOpt, "exec", testContainerName, "sh", "-c", fmt.Sprintf("echo foo > %s/bar.txt", tmpfsDir))
-				command.Run(o.BaseOpt, "kill", testContainerName) // have to use kill to stop the container running with sleep infinity
-				command.Run(o.BaseOpt, "start", testContainerName)
-				command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName, "sh", "-c", fmt.Sprintf("cat %s/bar.txt", tmpfsDir))
+				command.Run(o.BaseOpt, "exec", testContainerName(), "sh", "-c", fmt.Sprintf("echo foo > %s/bar.txt", tmpfsDir))
+				command.Run(o.BaseOpt, "kill", testContainerName()) // have to use kill to stop the container running with sleep infinity
+				command.Run(o.BaseOpt, "start", testContainerName())
+				command.RunWithoutSuccessfulExit(o.BaseOpt, "exec", testContainerName(), "sh", "-c", fmt.Sprintf("cat %s/bar.txt", tmpfsDir))
 			})
 
 			ginkgo.It("should mount a volume using --mount type=volume flag", func() {
-				command.Run(o.BaseOpt, "run", "--name", testContainerName, "--mount",
-					fmt.Sprintf("type=volume,source=%s,target=%s", testVolumeName, destDir), localImages[defaultImage])
-				srcDir := command.StdoutStr(o.BaseOpt, "volume", "inspect", testVolumeName, "--format", "{{.Mountpoint}}")
+				command.Run(o.BaseOpt, "run", "--name", testContainerName(), "--mount",
+					fmt.Sprintf("type=volume,source=%s,target=%s", testVolumeName(), destDir), localImages[defaultImage])
+				srcDir := command.StdoutStr(o.BaseOpt, "volume", "inspect", testVolumeName(), "--format", "{{.Mountpoint}}")
 				expectedMount := []MountJSON{makeMount(volumeType, srcDir, destDir, "", true)}
-				actualMount := getContainerMounts(o.BaseOpt, testContainerName)
+				actualMount := getContainerMounts(o.BaseOpt, testContainerName())
 				verifyMountsInfo(actualMount, expectedMount)
 			})
 		})
 
 		// Cgroup version v2
-		ginkgo.When("running a container with resource flags", func() {
+		ginkgo.When("running a container with resource flags", ginkgo.Label(LabelNeedsCgroupV2), func() {
 			ginkgo.BeforeEach(func() {
-				if o.CGMode != Unified {
+				if o.cgMode() != Unified {
 					ginkgo.Skip("requires cgroup v2 to test resource flags")
 				}
 			})
@@ -790,19 +787,3 @@ func verifyMountsInfo(actual []MountJSON, want []MountJSON) {
 		}
 	}
 }
-
-func getWslPath(winPath string) (string, error) {
-	path, err := filepath.Abs(filepath.Clean(winPath))
-	if err != nil {
-		return "", err
-	}
-	if len(path) >= 2 && path[1] == ':' {
-		drive := strings.ToLower(string(path[0]))
-		remainingPath := ""
-		if len(path) > 3 {
-			remainingPath = path[3:]
-		}
-		return filepath.ToSlash(filepath.Join(string(filepath.Separator), "mnt", drive, remainingPath)), nil
-	}
-	return path, nil
-}
@@ -28,7 +28,7 @@ type imageManifest struct {
 
 // Save tests saving an image to a tar archive.
 func Save(o *option.Option) {
-	ginkgo.Describe("save an image", func() {
+	ginkgo.Describe("save an image", labelsOf("Save"), ginkgo.Serial, func() {
 		var tarFilePath string
 		var tarFileContext string
 		ginkgo.BeforeEach(func() {
@@ -12,40 +12,56 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Start tests starting a container.
 func Start(o *option.Option) {
-	ginkgo.Describe("start a container", func() {
+	ginkgo.Describe("start a container", labelsOf("Start"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should start the container if it is in Exited status", func() {
-			command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "nc", "-l")
-			containerShouldBeRunning(o, testContainerName)
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "nc", "-l")
+			containerShouldBeRunning(o, testContainerName())
 
-			command.Run(o, "stop", testContainerName)
-			command.RunWithoutSuccessfulExit(o, "exec", testContainerName, "echo", "foo")
+			command.Run(o, "stop", testContainerName())
+			command.RunWithoutSuccessfulExit(o, "exec", testContainerName(), "echo", "foo")
 
-			command.Run(o, "start", testContainerName)
-			containerShouldBeRunning(o, testContainerName)
+			command.Run(o, "start", testContainerName())
+			containerShouldBeRunning(o, testContainerName())
 		})
 
 		for _, attach := range []string{"--attach", "-a", "-a=true", "--attach=true"} {
 			ginkgo.It(fmt.Sprintf("with %s flag, should start the container with stdout", attach), func() {
-				command.Run(o, "create", "--name", testContainerName, localImages[defaultImage], "echo", "foo")
-				output := command.StdoutStr(o, "start", attach, testContainerName)
+				command.Run(o, "create", "--name", testContainerName(), localImages[defaultImage], "echo", "foo")
+				output := command.StdoutStr(o, "start", attach, testContainerName())
 				gomega.Expect(output).To(gomega.Equal("foo"))
 			})
 		}
 
+		ginkgo.When("the container is created with a TTY", ginkgo.Label(LabelNeedsTerminal), func() {
+			ginkgo.BeforeEach(func() {
+				command.Run(o, "create", "-it", "--name", testContainerName(), localImages[defaultImage], "sh")
+			})
+
+			ginkgo.It("should start the container and attach the terminal to it with -ai flags", func() {
+				terminal := command.StartInTerminal(o, "start", "-ai", testContainerName())
+				shouldRespondInTerminal(terminal)
+				shouldDetachInTerminal(o, terminal, command.DefaultDetachKeys)
+			})
+
+			ginkgo.It("should detach from the container with the keys specified by --detach-keys flag", func() {
+				terminal := command.StartInTerminal(o, "start", "-ai", "--detach-keys", customDetachKeys, testContainerName())
+				shouldRespondInTerminal(terminal)
+				shouldDetachInTerminal(o, terminal, customDetachKeys)
+			})
+		})
+
 		ginkgo.It("should run a container without an init process when --init=false flag is used", func() {
-			command.Run(o, "run", "--name", testContainerName, "--init=false", localImages[defaultImage], "ps", "-ao", "pid,comm")
-			psOutput := command.StdoutStr(o, "logs", testContainerName)
+			command.Run(o, "run", "--name", testContainerName(), "--init=false", localImages[defaultImage], "ps", "-ao", "pid,comm")
+			psOutput := command.StdoutStr(o, "logs", testContainerName())
 
 			// Split the output into lines
 			lines := strings.Split(strings.TrimSpace(psOutput), "\n")
@@ -9,33 +9,31 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Stats tests displaying container resource usage statistics.
 func Stats(o *option.Option) {
-	ginkgo.Describe("display a container", func() {
+	ginkgo.Describe("display a container", labelsOf("Stats"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 		// TODO: add tests for -a flag
 		// REF issue: https://github.com/containerd/nerdctl/issues/1415
 		// TODO: add test for streaming data
 		ginkgo.When("the container is running", func() {
 			ginkgo.BeforeEach(func() {
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
 			})
 
 			ginkgo.It("should disable streaming usage stats and print result with --no-stream flag", func() {
-				output := command.StdoutStr(o, "stats", "--no-stream", testContainerName, "--format", "{{.Name}}")
-				gomega.Expect(output).Should(gomega.Equal(testContainerName))
+				output := command.StdoutStr(o, "stats", "--no-stream", testContainerName(), "--format", "{{.Name}}")
+				gomega.Expect(output).Should(gomega.Equal(testContainerName()))
 			})
 
 			ginkgo.It("should not truncate output with --no-trunc flag", func() {
-				noTruncated := command.StdoutStr(o, "stats", "--no-stream", "--no-trunc", testContainerName, "--format", "{{.ID}}")
-				truncated := command.StdoutStr(o, "stats", "--no-stream", testContainerName, "--format", "{{.ID}}")
+				noTruncated := command.StdoutStr(o, "stats", "--no-stream", "--no-trunc", testContainerName(), "--format", "{{.ID}}")
+				truncated := command.StdoutStr(o, "stats", "--no-stream", testContainerName(), "--format", "{{.ID}}")
 				gomega.Expect(len(noTruncated) > len(truncated)).Should(gomega.BeTrue())
 				gomega.Expect(noTruncated).Should(gomega.ContainSubstring(truncated))
 			})
@@ -12,36 +12,34 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // Stop tests stopping a container.
 func Stop(o *option.Option) {
-	ginkgo.Describe("stop a container", func() {
+	ginkgo.Describe("stop a container", labelsOf("Stop"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should stop the container if the container is running", func() {
-			command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "nc", "-l")
-			containerShouldBeRunning(o, testContainerName)
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "nc", "-l")
+			containerShouldBeRunning(o, testContainerName())
 
-			command.Run(o, "stop", testContainerName)
-			command.RunWithoutSuccessfulExit(o, "exec", testContainerName, "echo", "foo")
+			command.Run(o, "stop", testContainerName())
+			command.RunWithoutSuccessfulExit(o, "exec", testContainerName(), "echo", "foo")
 		})
 
 		for _, timeFlag := range []string{"-t", "--time"} {
 			ginkgo.It(fmt.Sprintf("should stop running container within specified time by %s flag", timeFlag), func() {
 				// With PID=1, `sleep infinity` does not exit due to receiving a SIGTERM, which is sent by the stop command.
 				// Ref. https://superuser.com/a/1299463/730265
-				command.Run(o, "run", "-d", "--name", testContainerName, localImages[defaultImage], "sleep", "infinity")
-				gomega.Expect(command.StdoutStr(o, "exec", testContainerName, "echo", "foo")).To(gomega.Equal("foo"))
+				command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+				gomega.Expect(command.StdoutStr(o, "exec", testContainerName(), "echo", "foo")).To(gomega.Equal("foo"))
 				startTime := time.Now()
-				command.Run(o, "stop", "-t", "1", testContainerName)
+				command.Run(o, "stop", "-t", "1", testContainerName())
 				gomega.Expect(time.Since(startTime)).To(gomega.BeNumerically("~", 1*time.Second, 1500*time.Millisecond))
-				command.RunWithoutSuccessfulExit(o, "exec", testContainerName, "echo", "foo")
+				command.RunWithoutSuccessfulExit(o, "exec", testContainerName(), "echo", "foo")
 			})
 		}
 	})
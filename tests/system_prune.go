@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/ffs"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// SystemPrune tests the "system prune" command that removes the unused containers, networks, images, volumes and build cache.
+func SystemPrune(o *option.Option) {
+	ginkgo.Describe("remove unused data", labelsOf("SystemPrune"), ginkgo.Serial, func() {
+		ginkgo.BeforeEach(func() {
+			if o.CleanupLabel() != "" {
+				ginkgo.Skip("system prune removes the objects that are not created by the tests")
+			}
+			command.RemoveAll(o)
+		})
+		ginkgo.AfterEach(func() {
+			command.RemoveAll(o)
+		})
+
+		for _, force := range []string{"--force", "-f"} {
+			ginkgo.It(fmt.Sprintf("should remove the stopped containers and the unused networks with %s flag", force), func() {
+				command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+				command.Run(o, "run", "-d", "--name", testContainerName2(), localImages[defaultImage], "sleep", "infinity")
+				command.Run(o, "network", "create", testNetwork())
+				command.Run(o, "system", "prune", force)
+				gomega.Expect(containerShouldNotExist(o, testContainerName())).To(gomega.Succeed())
+				containerShouldBeRunning(o, testContainerName2())
+				networkShouldNotExist(o, testNetwork())
+				networkShouldExist(o, bridgeNetwork)
+			})
+		}
+
+		ginkgo.It("should remove the stopped containers with inputting y in prompt confirmation", func() {
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			command.New(o, "system", "prune").WithStdin(gbytes.BufferWithBytes([]byte("y"))).Run()
+			gomega.Expect(containerShouldNotExist(o, testContainerName())).To(gomega.Succeed())
+		})
+
+		ginkgo.It("should not remove anything with inputting n in prompt confirmation", func() {
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			command.Run(o, "network", "create", testNetwork())
+			command.New(o, "system", "prune").WithStdin(gbytes.BufferWithBytes([]byte("n"))).Run()
+			containerShouldExist(o, testContainerName())
+			networkShouldExist(o, testNetwork())
+		})
+
+		ginkgo.It("should not remove the tagged images without --all flag", func() {
+			pullImage(o, localImages[defaultImage])
+			command.Run(o, "system", "prune", "--force")
+			imageShouldExist(o, localImages[defaultImage])
+		})
+
+		for _, all := range []string{"--all", "-a"} {
+			ginkgo.It(fmt.Sprintf("should remove the unused images with %s flag", all), func() {
+				pullImage(o, localImages[defaultImage])
+				command.Run(o, "system", "prune", "--force", all)
+				imageShouldNotExist(o, localImages[defaultImage])
+			})
+		}
+
+		ginkgo.It("should not remove the images used by a running container with --all flag", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "system", "prune", "--force", "--all")
+			imageShouldExist(o, localImages[defaultImage])
+			containerShouldBeRunning(o, testContainerName())
+		})
+
+		ginkgo.It("should only remove the unused anonymous volumes with --volumes flag", func() {
+			command.Run(o, "volume", "create", testVolumeName())
+			command.Run(o, "run", "-v", "/data", "--name", testContainerName(), localImages[defaultImage])
+			anonymousVolume := command.StdoutStr(o, "inspect", "--format", "{{range .Mounts}}{{.Name}}{{end}}", testContainerName())
+			gomega.Expect(anonymousVolume).ShouldNot(gomega.BeEmpty())
+			command.Run(o, "rm", testContainerName())
+
+			command.Run(o, "system", "prune", "--force")
+			volumeShouldExist(o, anonymousVolume)
+
+			command.Run(o, "system", "prune", "--force", "--volumes")
+			volumeShouldNotExist(o, anonymousVolume)
+			volumeShouldExist(o, testVolumeName())
+		})
+
+		ginkgo.It("should prune the build cache with --all flag", func() {
+			buildContext := ffs.CreateBuildContext(fmt.Sprintf(`FROM %s
+			RUN echo "system prune cache:$((1 + 1))"
+			`, localImages[defaultImage]))
+			ginkgo.DeferCleanup(os.RemoveAll, buildContext)
+			// There is no interface to validate the current builder cache size (see BuilderPrune),
+			// so the cache is checked by whether the output of the RUN instruction is printed again, i.e., it's not CACHED.
+			build := func() string {
+				return command.StderrStr(o, "build", "--progress=plain", "-t", testImageName(), buildContext)
+			}
+			gomega.Expect(build()).Should(gomega.ContainSubstring("system prune cache:2"))
+			gomega.Expect(build()).ShouldNot(gomega.ContainSubstring("system prune cache:2"))
+			command.Run(o, "system", "prune", "--force", "--all")
+			imageShouldNotExist(o, testImageName())
+			gomega.Expect(build()).Should(gomega.ContainSubstring("system prune cache:2"))
+		})
+
+		ginkgo.When("filtering the objects to remove", func() {
+			ginkgo.BeforeEach(func() {
+				testutil.RequireCapability(o, "system prune --filter")
+			})
+
+			ginkgo.It("should only remove the objects with the label with --filter label=", func() {
+				command.Run(o, "run", "--name", testContainerName(), "--label", "prune=true", localImages[defaultImage])
+				command.Run(o, "run", "--name", testContainerName2(), localImages[defaultImage])
+				command.Run(o, "system", "prune", "--force", "--filter", "label=prune=true")
+				gomega.Expect(containerShouldNotExist(o, testContainerName())).To(gomega.Succeed())
+				containerShouldExist(o, testContainerName2())
+			})
+
+			ginkgo.It("should only remove the objects created before the timestamp with --filter until=", func() {
+				const newNetwork = "test-network-new"
+				command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+				command.Run(o, "network", "create", testNetwork())
+				time.Sleep(5 * time.Second)
+				command.Run(o, "run", "--name", testContainerName2(), localImages[defaultImage])
+				command.Run(o, "network", "create", newNetwork)
+				command.Run(o, "system", "prune", "--force", "--filter", "until=3s")
+				gomega.Expect(containerShouldNotExist(o, testContainerName())).To(gomega.Succeed())
+				networkShouldNotExist(o, testNetwork())
+				containerShouldExist(o, testContainerName2())
+				networkShouldExist(o, newNetwork)
+			})
+		})
+	})
+}
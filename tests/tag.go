@@ -13,7 +13,7 @@ import (
 
 // Tag tests tagging a container image.
 func Tag(o *option.Option) {
-	ginkgo.Describe("tag a container image", func() {
+	ginkgo.Describe("tag a container image", labelsOf("Tag"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 		})
@@ -23,15 +23,15 @@ func Tag(o *option.Option) {
 		ginkgo.It("should tag an image when the image exists", func() {
 			pullImage(o, localImages[defaultImage])
 
-			command.Run(o, "tag", localImages[defaultImage], testImageName)
+			command.Run(o, "tag", localImages[defaultImage], testImageName())
 			defaultImageID := command.Stdout(o, "images", "--quiet", "--no-trunc", localImages[defaultImage])
-			taggedImageID := command.Stdout(o, "images", "--quiet", "--no-trunc", testImageName)
+			taggedImageID := command.Stdout(o, "images", "--quiet", "--no-trunc", testImageName())
 			gomega.Expect(taggedImageID).ShouldNot(gomega.BeEmpty())
 			gomega.Expect(taggedImageID).To(gomega.Equal(defaultImageID))
 		})
 		ginkgo.It("should not tag an image when the image doesn't exist", func() {
-			command.RunWithoutSuccessfulExit(o, "tag", nonexistentImageName, testImageName)
-			imageShouldNotExist(o, testImageName)
+			command.RunWithoutSuccessfulExit(o, "tag", nonexistentImageName, testImageName())
+			imageShouldNotExist(o, testImageName())
 		})
 	})
 }
@@ -10,7 +10,12 @@
 package tests
 
 import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,32 +23,38 @@ import (
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
-	"github.com/onsi/gomega/gexec"
 
 	"github.com/runfinch/common-tests/fnet"
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/ffs"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/registry"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 const (
 	alpineImage              = "public.ecr.aws/docker/library/alpine:latest"
-	testImageName            = "test:tag"
 	nonexistentImageName     = "ne-repo:ne-tag"
 	nonexistentContainerName = "ne-ctr"
-	testContainerName        = "ctr-test"
-	testContainerName2       = "ctr-test-2"
-	testVolumeName           = "testVol"
 	registryImage            = "public.ecr.aws/docker/library/registry:latest"
 	localRegistryName        = "local-registry"
 	testUser                 = "testUser"
 	testPassword             = "testPassword"
 	sha256RegexFull          = "^sha256:[a-f0-9]{64}$"
 	bridgeNetwork            = "bridge"
-	testNetwork              = "test-network"
 )
 
+// The names of the objects created by the specs. They're suffixed with the namespace of the running spec
+// (see testutil.UniqueName) so that the same names don't collide when the specs run in parallel,
+// and they can only be called while a spec is running.
+func testImageName() string      { return testutil.UniqueName("test") + ":tag" }
+func testContainerName() string  { return testutil.UniqueName("ctr-test") }
+func testContainerName2() string { return testutil.UniqueName("ctr-test-2") }
+func testVolumeName() string     { return testutil.UniqueName("testVol") }
+func testVolumeName2() string    { return testutil.UniqueName("testVol2") }
+func testNetwork() string        { return testutil.UniqueName("test-network") }
+
 type localImage string
 
 const (
@@ -53,6 +64,11 @@ const (
 	nginxImage        localImage = "nginxImage"
 )
 
+// registryImageName is the name of registryImage in option.ImageCatalog.
+const registryImageName = "registryImage"
+
+// remoteImages are the default references of the images used by the tests.
+// They can be overridden by option.WithImageCatalog.
 var remoteImages = map[localImage]string{
 	defaultImage:      alpineImage,
 	olderAlpineImage:  "public.ecr.aws/docker/library/alpine:3.13",
@@ -62,6 +78,12 @@ var remoteImages = map[localImage]string{
 
 var localImages = map[localImage]string{}
 
+// localRegistry is the local registry served from the test binary when option.WithInProcessRegistry is used.
+var localRegistry *registry.Registry
+
+// localRegistryObjects are the container and the image of the local registry, which are protected from the cleanup.
+var localRegistryObjects []string
+
 // CGMode is the cgroups mode of the host system.
 // We copy the struct from containerd/cgroups [1] instead of using it as a library
 // because it only builds on linux,
@@ -81,7 +103,12 @@ const (
 	// Unified with only cgroups v2 mounted.
 	Unified
 
-	retryPull = 3
+	retryPull        = 3
+	retryPullBackoff = 5 * time.Second
+
+	// retryRegistryReady and retryRegistryBackoff wait up to 10 seconds for a registry started by startRegistry to be ready.
+	retryRegistryReady   = 20
+	retryRegistryBackoff = 500 * time.Millisecond
 )
 
 // SetupLocalRegistry can be invoked before running the tests to save time when pulling images during tests.
@@ -89,42 +116,51 @@ const (
 // It spins up a local registry, tags all remoteImages, pushes the new tagged images to the local registry,
 // and changes adds corresponding entries to localImages for all of the new tags pushed to local registry.
 //
+// If o specifies an image archive directory (see option.WithImageArchiveDir), the images, including the registry image,
+// are loaded from the archives in that directory instead of being pulled from the internet.
+//
+// If o uses the in-process registry (see option.WithInProcessRegistry), the local registry is served from the test binary
+// instead of running as a container, so the registry image is not needed.
+//
+// When the specs run in parallel, it should only be invoked by one process,
+// and the others should be set up with LocalRegistryState and UseLocalRegistry.
+//
 // After all the tests are done, invoke CleanupLocalRegistry to clean up the local registry.
 func SetupLocalRegistry(o *option.Option) {
+	validateImageCatalog(o.ImageCatalog())
 	command.RemoveAll(o)
+	archiveDir := o.ImageArchiveDir()
+	if archiveDir != "" {
+		loadImageArchives(o, archiveDir)
+	}
 	hostPort := fnet.GetFreePort()
-	containerID := command.StdoutStr(o, "run", "-d", "-p",
-		fmt.Sprintf("%d:5000", hostPort), "--name", localRegistryName, registryImage)
-	imageID := command.StdoutStr(o, "images", "-q")
-	command.SetLocalRegistryContainerID(containerID)
-	command.SetLocalRegistryImageID(imageID)
-	command.SetLocalRegistryImageName(registryImage)
-
-	for k, ref := range remoteImages {
+	if o.UsesInProcessRegistry() {
+		localRegistry = registry.Start(hostPort)
+	} else {
+		registryRef := registryImageRef(o)
+		verifyImageDigest(o, registryImageName, registryRef)
+		containerID := command.StdoutStr(o, "run", "-d", "-p",
+			fmt.Sprintf("%d:5000", hostPort), "--name", localRegistryName, registryRef)
+		imageID := command.StdoutStr(o, "images", "-q", registryRef)
+		localRegistryObjects = []string{containerID, imageID, registryRef}
+		o.Protect(localRegistryObjects...)
+	}
+
+	for k := range remoteImages {
+		ref := remoteImageRef(o, k)
 		// split image tag according to spec
 		// https://github.com/distribution/distribution/blob/d0deff9cd6c2b8c82c6f3d1c713af51df099d07b/reference/reference.go
 		_, name, _ := strings.Cut(ref, "/")
-		// allow up to a minute for remote pulls to account for external network
-		// latency/throughput issues or throttling (default is 10 seconds)
-		// retry pull for 3 times.
-		var session *gexec.Session
-		exitCode := -1
-		for i := 0; i < retryPull; i++ {
-			session = command.New(o, "pull", ref).WithoutWait().Run()
-			select {
-			case <-session.Exited:
-				exitCode = session.ExitCode()
-			case <-time.After(30 * time.Second):
-				fmt.Printf("Timeout occurred, command hasn't exited yet (attempt %d)", i)
-				session.Kill()
-			}
-			if exitCode == 0 {
-				break
+		// The digest is dropped from the local reference because an image can only be tagged with a tag.
+		name, _, _ = strings.Cut(name, "@")
+		if archiveDir != "" {
+			if len(command.Stdout(o, "images", "-q", ref)) == 0 {
+				ginkgo.Fail(fmt.Sprintf("image %s is not found in the archives under %s", ref, archiveDir))
 			}
+		} else {
+			pullRemoteImage(o, ref)
 		}
-		if exitCode != 0 {
-			ginkgo.Fail("Failed to pull image " + ref)
-		}
+		verifyImageDigest(o, string(k), ref)
 
 		localRef := fmt.Sprintf("localhost:%d/%s", hostPort, name)
 		command.Run(o, "tag", ref, localRef)
@@ -134,14 +170,196 @@ func SetupLocalRegistry(o *option.Option) {
 	}
 }
 
+// remoteImageRef returns the reference that the image k is pulled from, honoring the image catalog of o.
+func remoteImageRef(o *option.Option, k localImage) string {
+	if ref, ok := o.ImageCatalog()[string(k)]; ok {
+		return ref
+	}
+	return remoteImages[k]
+}
+
+// registryImageRef returns the reference of the registry image, honoring the image catalog of o.
+func registryImageRef(o *option.Option) string {
+	if ref, ok := o.ImageCatalog()[registryImageName]; ok {
+		return ref
+	}
+	return registryImage
+}
+
+// validateImageCatalog fails if c contains an image that is not used by the tests, which is most likely a typo.
+func validateImageCatalog(c option.ImageCatalog) {
+	for name := range c {
+		if _, ok := remoteImages[localImage(name)]; !ok && name != registryImageName {
+			ginkgo.Fail(fmt.Sprintf("image %s in the image catalog is not used by the tests", name))
+		}
+	}
+}
+
+// verifyImageDigest fails if ref is pinned by a digest in the image catalog of o but the image named name has a different digest.
+// The image is pulled first if it does not exist in the testing environment yet.
+func verifyImageDigest(o *option.Option, name, ref string) {
+	digest := o.ImageCatalog().Digest(name)
+	if digest == "" {
+		return
+	}
+	if len(command.Stdout(o, "images", "-q", ref)) == 0 {
+		command.New(o, "pull", "-q", ref).WithTimeoutInSeconds(60).Run()
+	}
+	repoDigests := command.StdoutStr(o, "image", "inspect", "--format", "{{json .RepoDigests}}", ref)
+	var digests []string
+	gomega.Expect(json.Unmarshal([]byte(repoDigests), &digests)).Should(gomega.Succeed())
+	gomega.Expect(digests).Should(gomega.ContainElement(gomega.HaveSuffix("@"+digest)),
+		fmt.Sprintf("image %s is expected to have digest %s", ref, digest))
+}
+
+// pullRemoteImage pulls ref from its remote registry.
+func pullRemoteImage(o *option.Option, ref string) {
+	// allow up to 30 seconds for each remote pull to account for external network
+	// latency/throughput issues or throttling (default is 10 seconds), and retry the pull for 3 times.
+	command.New(o, "pull", ref).WithTimeoutInSeconds(30).WithRetry(retryPull, retryPullBackoff, nil).Run()
+}
+
+// loadImageArchives loads all the image archives under dir.
+// A regular file is treated as a tarball produced by `save`,
+// and a directory containing an oci-layout file is treated as an OCI image layout.
+func loadImageArchives(o *option.Option, dir string) {
+	entries, err := os.ReadDir(dir)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	loaded := 0
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case entry.Type().IsRegular():
+			command.New(o, "load", "-i", path).WithTimeoutInSeconds(60).Run()
+		case entry.IsDir() && ffs.CheckIfFileExists(filepath.Join(path, "oci-layout")):
+			command.New(o, "load").WithStdin(tarDir(path)).WithTimeoutInSeconds(60).Run()
+		default:
+			continue
+		}
+		loaded++
+	}
+	if loaded == 0 {
+		ginkgo.Fail(fmt.Sprintf("no image archives are found under %s", dir))
+	}
+}
+
+// tarDir archives the content of dir so that it can be piped to `load`.
+func tarDir(dir string) io.Reader {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			header.Name += "/"
+			return tw.WriteHeader(header)
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		file, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck // The file is only read.
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	gomega.Expect(tw.Close()).Should(gomega.Succeed())
+	return &buf
+}
+
+// localRegistryState is what the processes running the specs in parallel need to share the local registry.
+type localRegistryState struct {
+	Images    map[localImage]string
+	Protected []string
+}
+
+// LocalRegistryState returns the state of the local registry set up by SetupLocalRegistry in this process,
+// which is passed to UseLocalRegistry in the other processes when the specs run in parallel,
+// e.g., from the first function of ginkgo.SynchronizedBeforeSuite to the second one.
+func LocalRegistryState() []byte {
+	data, err := json.Marshal(localRegistryState{Images: localImages, Protected: localRegistryObjects})
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	return data
+}
+
+// UseLocalRegistry makes the specs use the local registry set up by SetupLocalRegistry in another process,
+// whose state is returned by LocalRegistryState.
+func UseLocalRegistry(o *option.Option, state []byte) {
+	var s localRegistryState
+	gomega.Expect(json.Unmarshal(state, &s)).Should(gomega.Succeed())
+	localImages = s.Images
+	localRegistryObjects = s.Protected
+	o.Protect(localRegistryObjects...)
+}
+
 // CleanupLocalRegistry removes the local registry container and image. It's used together with SetupLocalRegistry,
 // and should be invoked after running all the tests.
 func CleanupLocalRegistry(o *option.Option) {
+	localImages = map[localImage]string{}
+	if localRegistry != nil {
+		localRegistry.Close()
+		localRegistry = nil
+		return
+	}
+	registryRef := registryImageRef(o)
 	containerID := command.StdoutStr(o, "inspect", localRegistryName, "--format", "{{.ID}}")
 	command.Run(o, "rm", "-f", containerID)
-	imageID := command.StdoutStr(o, "images", "-q")
+	imageID := command.StdoutStr(o, "images", "-q", registryRef)
 	command.Run(o, "rmi", "-f", imageID)
-	localImages = map[localImage]string{}
+	o.Unprotect(containerID, imageID, registryRef)
+	localRegistryObjects = nil
+}
+
+// startRegistry starts a registry that lives until the end of the current spec and returns its address (e.g., localhost:5000).
+// If htpasswd is not empty, the clients have to log in with one of the credentials in it.
+//
+// The registry is served from the test binary if o uses the in-process registry, or runs as a container otherwise.
+func startRegistry(o *option.Option, htpasswd string) string {
+	port := fnet.GetFreePort()
+	if o.UsesInProcessRegistry() {
+		var modifiers []registry.Modifier
+		if htpasswd != "" {
+			modifiers = append(modifiers, registry.WithHtpasswd(htpasswd))
+		}
+		r := registry.Start(port, modifiers...)
+		ginkgo.DeferCleanup(r.Close)
+		return r.Addr()
+	}
+
+	args := []string{"run", "-dp", fmt.Sprintf("%d:5000", port), "--name", "registry"}
+	if htpasswd != "" {
+		filename := "htpasswd"
+		htpasswdDir := filepath.Dir(ffs.CreateTempFile(filename, htpasswd))
+		ginkgo.DeferCleanup(os.RemoveAll, htpasswdDir)
+		args = append(args,
+			"-v", fmt.Sprintf("%s:/auth", htpasswdDir),
+			"-e", "REGISTRY_AUTH=htpasswd",
+			"-e", "REGISTRY_AUTH_HTPASSWD_REALM=Registry Realm",
+			"-e", fmt.Sprintf("REGISTRY_AUTH_HTPASSWD_PATH=/auth/%s", filename))
+	}
+	containerID := command.StdoutStr(o, append(args, registryImageRef(o))...)
+	// Wait for the registry to serve HTTP requests. "/" does not require the credentials even if htpasswd is not empty.
+	// The request is sent from the container because the test host may not reach the subject's network.
+	command.New(o, "exec", containerID, "wget", "-q", "-O", "/dev/null", "http://localhost:5000/").
+		WithRetry(retryRegistryReady, retryRegistryBackoff, nil).Run()
+	return fmt.Sprintf("localhost:%d", port)
 }
 
 func pullImage(o *option.Option, imageName string) {
@@ -206,6 +424,14 @@ func volumeShouldNotExist(o *option.Option, volumeName string) {
 		fmt.Sprintf("name=%s", volumeName))).To(gomega.BeEmpty())
 }
 
+func networkShouldExist(o *option.Option, networkName string) {
+	gomega.Expect(command.StdoutAsLines(o, "network", "ls", "--format", "{{.Name}}")).To(gomega.ContainElement(networkName))
+}
+
+func networkShouldNotExist(o *option.Option, networkName string) {
+	gomega.Expect(command.StdoutAsLines(o, "network", "ls", "--format", "{{.Name}}")).NotTo(gomega.ContainElement(networkName))
+}
+
 func fileShouldExist(path, content string) {
 	gomega.Expect(path).To(gomega.BeARegularFile())
 	actualContent, err := os.ReadFile(filepath.Clean(path))
@@ -217,6 +443,40 @@ func fileShouldNotExist(path string) {
 	gomega.Expect(path).ToNot(gomega.BeAnExistingFile())
 }
 
+// subjectDir creates a temporary directory and makes it available to the subject (see option.Option.Stage)
+// so that the subject can write files in it, e.g., with cp or --cidfile.
+func subjectDir(o *option.Option, prefix string) string {
+	dir := ffs.CreateTempDir(prefix)
+	ginkgo.DeferCleanup(os.RemoveAll, dir)
+	gomega.Expect(o.Stage(dir)).To(gomega.Succeed())
+	return dir
+}
+
+// subjectFile returns the content of the file that the subject writes at the host path.
+// The file is read through a container that bind-mounts its directory because it's not copied back
+// to the host that runs the tests if the subject runs on another one (see option.SSHTransport).
+func subjectFile(o *option.Option, path string) string {
+	return string(command.Stdout(o, subjectFileArgs(path, "cat")...))
+}
+
+// subjectFileShouldExist checks the content of the file that the subject writes at the host path. See subjectFile.
+func subjectFileShouldExist(o *option.Option, path, content string) {
+	gomega.Expect(subjectFile(o, path)).To(gomega.Equal(content))
+}
+
+// subjectFileShouldNotExist checks that the subject does not write a file at the host path. See subjectFile.
+func subjectFileShouldNotExist(o *option.Option, path string) {
+	command.Run(o, subjectFileArgs(path, "test", "!", "-e")...)
+}
+
+// subjectFileArgs returns the arguments to run args against the file at the host path in a container
+// that bind-mounts the directory of the file.
+func subjectFileArgs(path string, args ...string) []string {
+	const mountPoint = "/subject-dir"
+	runArgs := []string{"run", "--rm", "-v", filepath.Dir(path) + ":" + mountPoint, localImages[defaultImage]}
+	return append(append(runArgs, args...), mountPoint+"/"+filepath.Base(path))
+}
+
 func fileShouldExistInContainer(o *option.Option, containerName, path, content string) {
 	gomega.Expect(command.StdoutStr(o, "exec", containerName, "cat", path)).To(gomega.Equal(content))
 }
@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var fakeSubject string
+
+// TestTests runs the specs of the helpers in this package against the fake subject (see the fake package).
+func TestTests(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Tests Suite")
+}
+
+var _ = ginkgo.SynchronizedBeforeSuite(func() []byte {
+	path, err := gexec.Build("github.com/runfinch/common-tests/fake/cmd/fake-subject")
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	return []byte(path)
+}, func(path []byte) {
+	fakeSubject = string(path)
+})
+
+var _ = ginkgo.SynchronizedAfterSuite(func() {}, func() {
+	gexec.CleanupBuildArtifacts()
+})
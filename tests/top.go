@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// Top tests the "top" command that displays the running processes of a container.
+func Top(o *option.Option) {
+	ginkgo.Describe("display the running processes of a container", labelsOf("Top"), func() {
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o)
+		})
+
+		ginkgo.It("should list the processes of a running container", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			lines := command.StdoutAsLines(o, "top", testContainerName())
+			gomega.Expect(lines).To(gomega.HaveLen(2))
+			gomega.Expect(lines[0]).To(gomega.ContainSubstring("PID"))
+			gomega.Expect(lines[1]).To(gomega.ContainSubstring("sleep infinity"))
+		})
+
+		ginkgo.It("should list the processes started by exec", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "exec", "-d", testContainerName(), "sleep", "12345")
+			gomega.Eventually(func() string {
+				return command.StdoutStr(o, "top", testContainerName())
+			}).Should(gomega.ContainSubstring("sleep 12345"))
+		})
+
+		ginkgo.It("should pass the ps options to ps", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			lines := command.StdoutAsLines(o, "top", testContainerName(), "-o", "pid,args")
+			gomega.Expect(lines).To(gomega.HaveLen(2))
+			gomega.Expect(lines[0]).To(gomega.MatchRegexp(`^\s*PID\s+(ARGS|COMMAND)\s*$`))
+			gomega.Expect(lines[1]).To(gomega.MatchRegexp(`^\s*\d+\s+sleep infinity\s*$`))
+		})
+
+		ginkgo.It("should not list the processes of a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "top", nonexistentContainerName)
+		})
+
+		ginkgo.It("should not list the processes of a stopped container", func() {
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage])
+			command.RunWithoutSuccessfulExit(o, "top", testContainerName())
+		})
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// Unpause tests the "unpause" command that resumes all processes within one or more paused containers.
+func Unpause(o *option.Option) {
+	ginkgo.Describe("unpause a container", labelsOf("Unpause"), func() {
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o)
+		})
+
+		ginkgo.It("should unpause a paused container", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "pause", testContainerName())
+			command.Run(o, "unpause", testContainerName())
+			state := inspect.Container(o, testContainerName()).State
+			gomega.Expect(state.Status).To(gomega.Equal("running"))
+			gomega.Expect(state.Running).To(gomega.BeTrue())
+			gomega.Expect(state.Paused).To(gomega.BeFalse())
+			gomega.Expect(command.StdoutStr(o, "exec", testContainerName(), "echo", "foo")).To(gomega.Equal("foo"))
+		})
+
+		ginkgo.It("should unpause multiple paused containers", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "run", "-d", "--name", testContainerName2(), localImages[defaultImage], "sleep", "infinity")
+			command.Run(o, "pause", testContainerName(), testContainerName2())
+			command.Run(o, "unpause", testContainerName(), testContainerName2())
+			containerShouldBeRunning(o, testContainerName(), testContainerName2())
+		})
+
+		ginkgo.It("should not unpause a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "unpause", nonexistentContainerName)
+		})
+
+		ginkgo.It("should not unpause a running container that is not paused", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			command.RunWithoutSuccessfulExit(o, "unpause", testContainerName())
+			gomega.Expect(inspect.Container(o, testContainerName()).State.Status).To(gomega.Equal("running"))
+		})
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// UpdateOption is the custom option to run tests in update.go.
+type UpdateOption struct {
+	// BaseOpt instructs how to run the test subject.
+	BaseOpt *option.Option
+	// CGMode is the cgroup mode that the host uses. Use DetectCGMode to detect it.
+	CGMode CGMode
+	// detectCGMode detects the cgroup mode with DetectCGMode when the specs run instead of using CGMode.
+	detectCGMode bool
+}
+
+// cgroupLimit is a resource limit of a container and where it can be read from inside the container.
+type cgroupLimit struct {
+	flags []string
+	// v2File and v2Values are the file under /sys/fs/cgroup and its possible values with cgroup v2.
+	v2File   string
+	v2Values []string
+	// v1File and v1Value are the file under /sys/fs/cgroup and its value with cgroup v1.
+	v1File  string
+	v1Value string
+}
+
+// Update tests the "update" command that updates the resource limits and the restart policy of running containers.
+func Update(o *UpdateOption) {
+	limits := []cgroupLimit{
+		{
+			flags:  []string{"--cpus", "0.42"},
+			v2File: "cpu.max", v2Values: []string{"42000 100000"},
+			v1File: "cpu/cpu.cfs_quota_us", v1Value: "42000",
+		},
+		{
+			flags:  []string{"--cpu-quota", "42000", "--cpu-period", "100000"},
+			v2File: "cpu.max", v2Values: []string{"42000 100000"},
+			v1File: "cpu/cpu.cfs_quota_us", v1Value: "42000",
+		},
+		{
+			// Different runc versions <1.3.2 will produce different values: 170 (newer) or 77 (older). See Run.
+			flags:  []string{"--cpu-shares", "2000"},
+			v2File: "cpu.weight", v2Values: []string{"170", "77"},
+			v1File: "cpu/cpu.shares", v1Value: "2000",
+		},
+		{
+			flags:  []string{"--cpuset-cpus", "0"},
+			v2File: "cpuset.cpus", v2Values: []string{"0"},
+			v1File: "cpuset/cpuset.cpus", v1Value: "0",
+		},
+		{
+			flags:  []string{"--memory", "42m"},
+			v2File: "memory.max", v2Values: []string{"44040192"},
+			v1File: "memory/memory.limit_in_bytes", v1Value: "44040192",
+		},
+		{
+			flags:  []string{"--memory-reservation", "6m"},
+			v2File: "memory.low", v2Values: []string{"6291456"},
+			v1File: "memory/memory.soft_limit_in_bytes", v1Value: "6291456",
+		},
+		{
+			flags:  []string{"--pids-limit", "42"},
+			v2File: "pids.max", v2Values: []string{"42"},
+			v1File: "pids/pids.max", v1Value: "42",
+		},
+	}
+
+	ginkgo.Describe("update a container", labelsOf("Update"), func() {
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o.BaseOpt)
+		})
+
+		ginkgo.When("the container is running", func() {
+			ginkgo.BeforeEach(func() {
+				command.Run(o.BaseOpt, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sleep", "infinity")
+			})
+
+			ginkgo.When("updating the resource limits", ginkgo.Label(LabelNeedsCgroup), func() {
+				var mode CGMode
+				ginkgo.BeforeEach(func() {
+					mode = o.cgMode()
+					if mode == Unavailable {
+						ginkgo.Skip("requires cgroup to test resource limits")
+					}
+					if mode != Unified && testutil.ProbeCapabilities(o.BaseOpt).Rootless {
+						ginkgo.Skip("requires cgroup v2 to test resource limits in rootless mode")
+					}
+				})
+
+				for _, limit := range limits {
+					ginkgo.It(fmt.Sprintf("should update the limit with %s", strings.Join(limit.flags, " ")), func() {
+						args := append([]string{"update"}, limit.flags...)
+						command.Run(o.BaseOpt, append(args, testContainerName())...)
+						file, values := limit.v2File, limit.v2Values
+						if mode != Unified {
+							file, values = limit.v1File, []string{limit.v1Value}
+						}
+						actual := command.StdoutStr(o.BaseOpt, "exec", testContainerName(), "cat", path.Join("/sys/fs/cgroup", file))
+						gomega.Expect(values).To(gomega.ContainElement(actual))
+						containerShouldBeRunning(o.BaseOpt, testContainerName())
+					})
+				}
+
+				ginkgo.It("should update multiple limits at once and report them in inspect", func() {
+					command.Run(o.BaseOpt, "update", "--cpus", "0.5", "--memory", "42m", testContainerName())
+					hostConfig := inspect.Container(o.BaseOpt, testContainerName()).HostConfig
+					gomega.Expect(hostConfig.NanoCPUs).To(gomega.Equal(int64(500000000)))
+					gomega.Expect(hostConfig.Memory).To(gomega.Equal(int64(44040192)))
+				})
+
+				ginkgo.It("should not update the limits with an invalid value", func() {
+					command.RunWithoutSuccessfulExit(o.BaseOpt, "update", "--memory", "invalid", testContainerName())
+					command.RunWithoutSuccessfulExit(o.BaseOpt, "update", "--cpus", "-1", testContainerName())
+					containerShouldBeRunning(o.BaseOpt, testContainerName())
+				})
+			})
+
+			for _, policy := range []string{"always", "unless-stopped", "on-failure:3", "no"} {
+				ginkgo.It(fmt.Sprintf("should update the restart policy to %s with --restart flag", policy), func() {
+					command.Run(o.BaseOpt, "update", "--restart", policy, testContainerName())
+					name, count, _ := strings.Cut(policy, ":")
+					restartPolicy := inspect.Container(o.BaseOpt, testContainerName()).HostConfig.RestartPolicy
+					gomega.Expect(restartPolicy.Name).To(gomega.Equal(name))
+					if count != "" {
+						gomega.Expect(fmt.Sprint(restartPolicy.MaximumRetryCount)).To(gomega.Equal(count))
+					}
+				})
+			}
+
+			ginkgo.It("should not update the restart policy to an invalid one", func() {
+				command.RunWithoutSuccessfulExit(o.BaseOpt, "update", "--restart", "sometimes", testContainerName())
+			})
+		})
+
+		ginkgo.It("should not update a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o.BaseOpt, "update", "--memory", "42m", nonexistentContainerName)
+		})
+	})
+}
+
+// cgMode returns CGMode, or detects it with DetectCGMode if the UpdateOption is created by All.
+func (o *UpdateOption) cgMode() CGMode {
+	if o.detectCGMode {
+		return DetectCGMode(o.BaseOpt)
+	}
+	return o.CGMode
+}
@@ -11,30 +11,28 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // VolumeCreate tests "volume create" command that creates a volume.
 func VolumeCreate(o *option.Option) {
-	ginkgo.Describe("create a volume", func() {
+	ginkgo.Describe("create a volume", labelsOf("VolumeCreate"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 
 		ginkgo.It("should create a volume with name", func() {
-			command.Run(o, "volume", "create", testVolumeName)
-			volumeShouldExist(o, testVolumeName)
+			command.Run(o, "volume", "create", testVolumeName())
+			volumeShouldExist(o, testVolumeName())
 		})
 
 		ginkgo.It("data in volume should be shared between containers", func() {
-			command.Run(o, "volume", "create", testVolumeName)
+			command.Run(o, "volume", "create", testVolumeName())
 			command.Run(
 				o,
 				"run",
 				"-v",
-				fmt.Sprintf("%s:/tmp", testVolumeName),
+				fmt.Sprintf("%s:/tmp", testVolumeName()),
 				localImages[defaultImage],
 				"sh", "-c", "echo foo > /tmp/test.txt",
 			)
@@ -42,7 +40,7 @@ func VolumeCreate(o *option.Option) {
 				o,
 				"run",
 				"-v",
-				fmt.Sprintf("%s:/tmp", testVolumeName),
+				fmt.Sprintf("%s:/tmp", testVolumeName()),
 				localImages[defaultImage],
 				"cat",
 				"/tmp/test.txt",
@@ -51,15 +49,15 @@ func VolumeCreate(o *option.Option) {
 		})
 
 		ginkgo.It("should create a volume with label with --label flag", func() {
-			command.Run(o, "volume", "create", "--label", "label=tag", testVolumeName)
-			output := command.StdoutStr(o, "volume", "inspect", testVolumeName, "--format", "{{.Labels.label}}")
+			command.Run(o, "volume", "create", "--label", "label=tag", testVolumeName())
+			output := command.StdoutStr(o, "volume", "inspect", testVolumeName(), "--format", "{{.Labels.label}}")
 			gomega.Expect(output).Should(gomega.Equal("tag"))
 		})
 
 		ginkgo.It("should create multiple labels with --label flag", func() {
-			command.Run(o, "volume", "create", "--label", "label=tag", "--label", "label1=tag1", testVolumeName)
-			tag := command.StdoutStr(o, "volume", "inspect", testVolumeName, "--format", "{{.Labels.label}}")
-			tag1 := command.StdoutStr(o, "volume", "inspect", testVolumeName, "--format", "{{.Labels.label1}}")
+			command.Run(o, "volume", "create", "--label", "label=tag", "--label", "label1=tag1", testVolumeName())
+			tag := command.StdoutStr(o, "volume", "inspect", testVolumeName(), "--format", "{{.Labels.label}}")
+			tag1 := command.StdoutStr(o, "volume", "inspect", testVolumeName(), "--format", "{{.Labels.label1}}")
 			gomega.Expect(tag).Should(gomega.Equal("tag"))
 			gomega.Expect(tag1).Should(gomega.Equal("tag1"))
 		})
@@ -69,8 +67,8 @@ func VolumeCreate(o *option.Option) {
 			if o.IsNerdctlV2() {
 				ginkgo.Skip("Behavior is not supported on nerdctl v2")
 			}
-			command.Run(o, "volume", "create", testVolumeName)
-			command.RunWithoutSuccessfulExit(o, "volume", "create", testVolumeName)
+			command.Run(o, "volume", "create", testVolumeName())
+			command.RunWithoutSuccessfulExit(o, "volume", "create", testVolumeName())
 		})
 
 		ginkgo.It("should warn volume already exists if a volume with the same name exists", func() {
@@ -78,10 +76,10 @@ func VolumeCreate(o *option.Option) {
 			if o.IsNerdctlV1() {
 				ginkgo.Skip("Behavior is not supported on nerdctl v1")
 			}
-			command.Run(o, "volume", "create", testVolumeName)
-			session := command.Run(o, "volume", "create", testVolumeName)
+			command.Run(o, "volume", "create", testVolumeName())
+			session := command.Run(o, "volume", "create", testVolumeName())
 			gomega.Expect(string(session.Err.Contents())).Should(gomega.ContainSubstring("already exists"))
-			gomega.Expect(string(session.Out.Contents())).Should(gomega.ContainSubstring(testVolumeName))
+			gomega.Expect(string(session.Out.Contents())).Should(gomega.ContainSubstring(testVolumeName()))
 		})
 	})
 }
@@ -11,30 +11,27 @@ import (
 
 	"github.com/runfinch/common-tests/command"
 	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
 )
 
 // VolumeLs tests "volume ls" command that lists volumes.
 func VolumeLs(o *option.Option) {
-	ginkgo.Describe("list volumes", func() {
+	ginkgo.Describe("list volumes", labelsOf("VolumeLs"), func() {
 		ginkgo.BeforeEach(func() {
-			command.RemoveAll(o)
-		})
-		ginkgo.AfterEach(func() {
-			command.RemoveAll(o)
+			testutil.UseSpecNamespace(o)
 		})
 		// TODO: add test for --filter after upgrading to nerdctl v0.23
 		ginkgo.It("should display all the volumes", func() {
-			const testVol2 = "testVol2"
-			command.Run(o, "volume", "create", testVolumeName)
-			command.Run(o, "volume", "create", testVol2)
+			command.Run(o, "volume", "create", testVolumeName())
+			command.Run(o, "volume", "create", testVolumeName2())
 			lines := command.StdoutAsLines(o, "volume", "ls", "--format", "{{.Name}}")
-			gomega.Expect(lines).Should(gomega.ContainElements(testVolumeName, testVol2))
+			gomega.Expect(lines).Should(gomega.ContainElements(testVolumeName(), testVolumeName2()))
 		})
 
 		for _, quiet := range []string{"--quiet", "-q"} {
 			ginkgo.It(fmt.Sprintf("should only display volume names with %s flag", quiet), func() {
-				command.Run(o, "volume", "create", testVolumeName)
-				gomega.Expect(command.StdoutAsLines(o, "volume", "ls", quiet)).Should(gomega.ContainElement(testVolumeName))
+				command.Run(o, "volume", "create", testVolumeName())
+				gomega.Expect(command.StdoutAsLines(o, "volume", "ls", quiet)).Should(gomega.ContainElement(testVolumeName()))
 			})
 		}
 	})
@@ -15,7 +15,7 @@ import (
 
 // VolumePrune tests "volume prune" command that removes all unused volumes.
 func VolumePrune(o *option.Option) {
-	ginkgo.Describe("remove all unused volumes", func() {
+	ginkgo.Describe("remove all unused volumes", labelsOf("VolumePrune"), ginkgo.Serial, func() {
 		ginkgo.BeforeEach(func() {
 			command.RemoveAll(o)
 		})
@@ -24,28 +24,28 @@ func VolumePrune(o *option.Option) {
 		})
 
 		ginkgo.It("should not remove a volume if it is used by a container", func() {
-			command.Run(o, "run", "-v", fmt.Sprintf("%s:/tmp", testVolumeName), "--name", testContainerName, localImages[defaultImage])
+			command.Run(o, "run", "-v", fmt.Sprintf("%s:/tmp", testVolumeName()), "--name", testContainerName(), localImages[defaultImage])
 			command.Run(o, "volume", "prune", "--force", "--all")
-			volumeShouldExist(o, testVolumeName)
+			volumeShouldExist(o, testVolumeName())
 		})
 
 		ginkgo.It("should remove all unused volumes with inputting y in prompt confirmation", func() {
-			command.Run(o, "volume", "create", testVolumeName)
+			command.Run(o, "volume", "create", testVolumeName())
 			command.New(o, "volume", "prune", "--all").WithStdin(gbytes.BufferWithBytes([]byte("y"))).Run()
-			volumeShouldNotExist(o, testVolumeName)
+			volumeShouldNotExist(o, testVolumeName())
 		})
 
 		ginkgo.It("should not remove all unused volumes with inputting n in prompt confirmation", func() {
-			command.Run(o, "volume", "create", testVolumeName)
+			command.Run(o, "volume", "create", testVolumeName())
 			command.New(o, "volume", "prune", "--all").WithStdin(gbytes.BufferWithBytes([]byte("n"))).Run()
-			volumeShouldExist(o, testVolumeName)
+			volumeShouldExist(o, testVolumeName())
 		})
 
 		for _, force := range []string{"--force", "-f"} {
 			ginkgo.It(fmt.Sprintf("should remove all unused volumes without prompting for confirmation with %s flag", force), func() {
-				command.Run(o, "volume", "create", testVolumeName)
+				command.Run(o, "volume", "create", testVolumeName())
 				command.Run(o, "volume", "prune", force, "--all")
-				volumeShouldNotExist(o, testVolumeName)
+				volumeShouldNotExist(o, testVolumeName())
 			})
 		}
 	})
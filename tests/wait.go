@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package tests
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/inspect"
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// Wait tests the "wait" command that blocks until one or more containers stop and prints their exit codes.
+func Wait(o *option.Option) {
+	ginkgo.Describe("wait for a container", labelsOf("Wait"), func() {
+		ginkgo.BeforeEach(func() {
+			testutil.UseSpecNamespace(o)
+		})
+
+		ginkgo.It("should wait for a running container to stop and print its exit code", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sh", "-c", "sleep 2; exit 42")
+			gomega.Expect(command.StdoutStr(o, "wait", testContainerName())).To(gomega.Equal("42"))
+			state := inspect.Container(o, testContainerName()).State
+			gomega.Expect(state.Status).To(gomega.Equal("exited"))
+			gomega.Expect(state.ExitCode).To(gomega.Equal(42))
+		})
+
+		ginkgo.It("should print the exit code of a stopped container", func() {
+			command.Run(o, "run", "--name", testContainerName(), localImages[defaultImage], "true")
+			gomega.Expect(command.StdoutStr(o, "wait", testContainerName())).To(gomega.Equal("0"))
+		})
+
+		ginkgo.It("should print the exit codes of multiple containers in order", func() {
+			command.Run(o, "run", "-d", "--name", testContainerName(), localImages[defaultImage], "sh", "-c", "sleep 1; exit 1")
+			command.Run(o, "run", "-d", "--name", testContainerName2(), localImages[defaultImage], "sh", "-c", "sleep 1; exit 2")
+			gomega.Expect(command.StdoutAsLines(o, "wait", testContainerName(), testContainerName2())).To(gomega.Equal([]string{"1", "2"}))
+		})
+
+		ginkgo.It("should not wait for a nonexistent container", func() {
+			command.RunWithoutSuccessfulExit(o, "wait", nonexistentContainerName)
+		})
+	})
+}
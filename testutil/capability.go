@@ -0,0 +1,220 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+)
+
+// Capabilities describes what the subject supports. It's discovered from the subject by ProbeCapabilities.
+type Capabilities struct {
+	// Commands are the top-level subcommands, e.g., "build" and "compose".
+	Commands []string
+	// Versions are the versions of the components reported by "version" (see option.GetComponentVersions), if any.
+	Versions map[string]string
+	// Snapshotter is the snapshotter (the storage driver) reported by "info", e.g., overlayfs.
+	Snapshotter string
+	// CgroupVersion is the cgroup version reported by "info", e.g., 2.
+	CgroupVersion string
+	// Rootless is true if the subject runs in rootless mode.
+	Rootless bool
+	// NetworkDrivers are the network drivers that can be used by "network create", e.g., bridge and macvlan.
+	NetworkDrivers []string
+
+	o     *option.Option
+	mu    sync.Mutex
+	helps map[string]help
+}
+
+var (
+	capabilitiesMu sync.Mutex
+	capabilities   = map[*option.Option]*Capabilities{}
+)
+
+// ProbeCapabilities discovers the capabilities of the subject of o from the output of "--help", "version" and "info".
+// The subject is only probed once per option, and the result is shared by the subsequent calls.
+// The help of the subcommands (e.g., "build --help") is only probed when a capability of them is needed (see Has).
+func ProbeCapabilities(o *option.Option) *Capabilities {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	if c, ok := capabilities[o]; ok {
+		return c
+	}
+
+	c := &Capabilities{o: o, helps: map[string]help{}}
+	c.Commands = c.help(nil).commands
+	c.Versions, _ = getComponentVersions(o)
+	if session := command.New(o, "info", "--format", "{{json .}}").WithoutCheckingExitCode().Run(); session.ExitCode() == 0 {
+		c.parseInfo(session.Out.Contents())
+	}
+	if len(c.NetworkDrivers) == 0 {
+		c.NetworkDrivers = parseNetworkDrivers(c.help([]string{"network", "create"}).text)
+	}
+	capabilities[o] = c
+	return c
+}
+
+// RequireCapability skips the current spec if the subject of o does not have all of the capabilities. See Capabilities.Has
+// for the format of a capability.
+//
+// For example, RequireCapability(o, "compose", "build --secret") skips the spec if the subject does not support
+// the compose subcommand or the --secret flag of the build subcommand.
+func RequireCapability(o *option.Option, capabilities ...string) {
+	c := ProbeCapabilities(o)
+	for _, capability := range capabilities {
+		has, err := c.Has(capability)
+		gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		if !has {
+			ginkgo.Skip(fmt.Sprintf("the subject does not have the capability %q", capability))
+		}
+	}
+}
+
+// Has reports whether the subject has the capability, which is one of the following:
+//
+//   - A subcommand, e.g., "compose" or "image prune".
+//   - A flag of a subcommand, e.g., "build --secret". The flag must be a long one.
+//   - "rootless" or "rootful".
+//   - "cgroup:<version>", e.g., "cgroup:2".
+//   - "snapshotter:<name>", e.g., "snapshotter:overlayfs".
+//   - "network-driver:<name>", e.g., "network-driver:macvlan".
+func (c *Capabilities) Has(capability string) (bool, error) {
+	switch kind, value, _ := strings.Cut(capability, ":"); {
+	case capability == "rootless":
+		return c.Rootless, nil
+	case capability == "rootful":
+		return !c.Rootless, nil
+	case kind == "cgroup":
+		return strings.TrimPrefix(value, "v") == c.CgroupVersion, nil
+	case kind == "snapshotter":
+		return value == c.Snapshotter, nil
+	case kind == "network-driver":
+		return slices.Contains(c.NetworkDrivers, value), nil
+	}
+
+	fields := strings.Fields(capability)
+	if len(fields) == 0 {
+		return false, fmt.Errorf("empty capability")
+	}
+	subcommand, flag := fields, ""
+	if last := fields[len(fields)-1]; strings.HasPrefix(last, "-") {
+		if !strings.HasPrefix(last, "--") || len(fields) == 1 {
+			return false, fmt.Errorf("invalid capability %q, the flag must be a long one of a subcommand", capability)
+		}
+		subcommand, flag = fields[:len(fields)-1], strings.TrimPrefix(last, "--")
+	}
+
+	for i := range subcommand {
+		if !slices.Contains(c.help(subcommand[:i]).commands, subcommand[i]) {
+			return false, nil
+		}
+	}
+	return flag == "" || slices.Contains(c.help(subcommand).flags, flag), nil
+}
+
+// help is the parsed output of "<subcommand> --help".
+type help struct {
+	text     string
+	commands []string
+	flags    []string
+}
+
+func (c *Capabilities) help(subcommand []string) help {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := strings.Join(subcommand, " ")
+	if h, ok := c.helps[key]; ok {
+		return h
+	}
+	var h help
+	session := command.New(c.o, append(slices.Clone(subcommand), "--help")...).WithoutCheckingExitCode().Run()
+	if session.ExitCode() == 0 {
+		h.text = string(session.Out.Contents())
+		h.commands, h.flags = parseHelp(h.text)
+	}
+	c.helps[key] = h
+	return h
+}
+
+var (
+	helpSectionRegex = regexp.MustCompile(`^\S.*:\s*$`)
+	helpCommandRegex = regexp.MustCompile(`^\s+([a-z][\w-]*)(?:,\s*[\w-]+)*(?:\s{2,}|\s*$)`)
+	helpFlagRegex    = regexp.MustCompile(`^\s+(?:-\w,\s+)?--([\w-]+)`)
+	quotedRegex      = regexp.MustCompile(`"([\w-]+)"`)
+)
+
+// parseHelp returns the subcommands and the long flags (without the leading dashes) listed in the output of --help,
+// which is in the format generated by cobra.
+func parseHelp(text string) (commands, flags []string) {
+	section := ""
+	for _, line := range strings.Split(text, "\n") {
+		if helpSectionRegex.MatchString(line) {
+			section = strings.ToLower(line)
+			continue
+		}
+		switch {
+		case strings.Contains(section, "commands"):
+			if m := helpCommandRegex.FindStringSubmatch(line); m != nil {
+				commands = append(commands, m[1])
+			}
+		case strings.Contains(section, "flags"):
+			if m := helpFlagRegex.FindStringSubmatch(line); m != nil {
+				flags = append(flags, m[1])
+			}
+		}
+	}
+	return commands, flags
+}
+
+// parseInfo parses the output of `info --format "{{json .}}"`.
+func (c *Capabilities) parseInfo(data []byte) {
+	var info struct {
+		Driver          string
+		CgroupVersion   string
+		SecurityOptions []string
+		Plugins         struct {
+			Network []string
+		}
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return
+	}
+	c.Snapshotter = info.Driver
+	c.CgroupVersion = info.CgroupVersion
+	c.NetworkDrivers = info.Plugins.Network
+	for _, opt := range info.SecurityOptions {
+		if slices.Contains(strings.Split(opt, ","), "name=rootless") {
+			c.Rootless = true
+		}
+	}
+}
+
+// parseNetworkDrivers parses the drivers listed in the description of the --driver flag in the output of "network create --help",
+// e.g., `-d, --driver string   Driver to manage the Network ("bridge"|"macvlan"|"ipvlan") (default "bridge")`.
+func parseNetworkDrivers(text string) []string {
+	for _, line := range strings.Split(text, "\n") {
+		if m := helpFlagRegex.FindStringSubmatch(line); m == nil || m[1] != "driver" {
+			continue
+		}
+		var drivers []string
+		for _, q := range quotedRegex.FindAllStringSubmatch(line, -1) {
+			if !slices.Contains(drivers, q[1]) {
+				drivers = append(drivers, q[1])
+			}
+		}
+		return drivers
+	}
+	return nil
+}
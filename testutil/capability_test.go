@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+const rootHelp = `nerdctl is a command line interface for containerd
+
+Usage: nerdctl [flags]
+
+Management commands:
+  builder    Manage builds
+  compose    Compose
+  network    Manage networks
+
+Commands:
+  build       Build an image from a Dockerfile. Needs buildkitd to be running.
+  run         Run a command in a new container
+
+Flags:
+  -H, --address string     containerd address
+      --debug              debug mode
+  -h, --help               help for nerdctl
+`
+
+const networkCreateHelp = `Create a network
+
+Usage: nerdctl network create [flags] NETWORK
+
+Flags:
+  -d, --driver string   Driver to manage the Network ("bridge"|"macvlan"|"ipvlan") (default "bridge")
+      --subnet string   Subnet in CIDR format
+`
+
+func TestParseHelp(t *testing.T) {
+	t.Parallel()
+
+	commands, flags := parseHelp(rootHelp)
+	if want := []string{"builder", "compose", "network", "build", "run"}; !reflect.DeepEqual(commands, want) {
+		t.Fatalf("expected commands %v, got %v", want, commands)
+	}
+	if want := []string{"address", "debug", "help"}; !reflect.DeepEqual(flags, want) {
+		t.Fatalf("expected flags %v, got %v", want, flags)
+	}
+}
+
+func TestParseNetworkDrivers(t *testing.T) {
+	t.Parallel()
+
+	if got, want := parseNetworkDrivers(networkCreateHelp), []string{"bridge", "macvlan", "ipvlan"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected drivers %v, got %v", want, got)
+	}
+}
+
+func TestHas(t *testing.T) {
+	t.Parallel()
+
+	c := &Capabilities{
+		CgroupVersion:  "2",
+		Snapshotter:    "overlayfs",
+		NetworkDrivers: []string{"bridge"},
+		helps: map[string]help{
+			"":      {commands: []string{"build", "compose"}},
+			"build": {flags: []string{"secret"}},
+		},
+	}
+	c.parseInfo([]byte(`{"SecurityOptions":["name=seccomp,profile=default","name=rootless"],"Driver":"native","CgroupVersion":"1"}`))
+
+	tests := []struct {
+		capability string
+		want       bool
+		wantErr    bool
+	}{
+		{capability: "compose", want: true},
+		{capability: "build --secret", want: true},
+		{capability: "build --ssh", want: false},
+		{capability: "push", want: false},
+		{capability: "rootless", want: true},
+		{capability: "rootful", want: false},
+		{capability: "cgroup:v1", want: true},
+		{capability: "cgroup:2", want: false},
+		{capability: "snapshotter:native", want: true},
+		{capability: "network-driver:macvlan", want: false},
+		{capability: "build -s", wantErr: true},
+		{capability: "", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := c.Has(test.capability)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Fatalf("Has(%q) = (%t, %v), want (%t, error: %t)", test.capability, got, err, test.want, test.wantErr)
+		}
+	}
+}
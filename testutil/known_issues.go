@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	xfailPrefix     = "XFAIL: "
+	xpassReportName = "XPASS"
+)
+
+// KnownIssue is a known issue of the subject that makes some specs fail.
+type KnownIssue struct {
+	// Spec is a regular expression matched against the full text of the specs, e.g., "Run a container image .* --cpus".
+	Spec string `yaml:"spec" json:"spec"`
+	// Label matches the specs with the label, e.g., the ones decorated with ginkgo.Label("compose").
+	Label string `yaml:"label" json:"label"`
+	// Reason describes the issue.
+	Reason string `yaml:"reason" json:"reason"`
+	// Link is the link to the issue, if any.
+	Link string `yaml:"link" json:"link"`
+	// Skip skips the matching specs instead of running them and expecting them to fail,
+	// e.g., when they hang or break the testing environment.
+	Skip bool `yaml:"skip" json:"skip"`
+
+	specRegex *regexp.Regexp
+}
+
+func (i *KnownIssue) matches(fullText string, labels []string) bool {
+	if i.specRegex != nil && !i.specRegex.MatchString(fullText) {
+		return false
+	}
+	return i.Label == "" || slices.Contains(labels, i.Label)
+}
+
+func (i *KnownIssue) String() string {
+	if i.Link == "" {
+		return i.Reason
+	}
+	return fmt.Sprintf("%s (%s)", i.Reason, i.Link)
+}
+
+// KnownIssues marks the specs matching the known issues of the subject as expected to fail,
+// so that downstream projects can tolerate them without forking the runner or removing whole test suites.
+//
+// An expected failure is reported as a skipped spec whose message starts with "XFAIL", and a matching spec that passes
+// is reported as passing with an "XPASS" report entry so that the issue can be removed.
+// Failures only count as expected if they go through the fail handler returned by FailHandler, e.g., failed assertions.
+type KnownIssues struct {
+	issues []KnownIssue
+	// xfailed is true if the running spec has failed as expected. Specs run sequentially in a Ginkgo process.
+	xfailed bool
+}
+
+// NewKnownIssues validates the known issues and creates a KnownIssues from them.
+// Every issue must have a Spec or a Label, and a Reason.
+func NewKnownIssues(issues ...KnownIssue) (*KnownIssues, error) {
+	issues = slices.Clone(issues)
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Spec == "" && issue.Label == "" {
+			return nil, fmt.Errorf("known issue %d must have a spec or a label", i)
+		}
+		if issue.Reason == "" {
+			return nil, fmt.Errorf("known issue %d must have a reason", i)
+		}
+		if issue.Spec != "" {
+			r, err := regexp.Compile(issue.Spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid spec of known issue %d: %w", i, err)
+			}
+			issue.specRegex = r
+		}
+	}
+	return &KnownIssues{issues: issues}, nil
+}
+
+// LoadKnownIssues loads the known issues from a YAML or JSON file containing a list of KnownIssue, e.g.,
+//
+//   - spec: Run a container image .* --cpus
+//     reason: cgroup v2 is not enabled
+//     link: https://github.com/runfinch/finch/issues/1
+//   - label: compose
+//     reason: compose is not supported yet
+//     skip: true
+func LoadKnownIssues(path string) (*KnownIssues, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known issues: %w", err)
+	}
+	var issues []KnownIssue
+	if err := yaml.Unmarshal(data, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse known issues %s: %w", path, err)
+	}
+	return NewKnownIssues(issues...)
+}
+
+// match returns the known issue matching the current spec, or nil if there is none.
+func (k *KnownIssues) match() *KnownIssue {
+	report := ginkgo.CurrentSpecReport()
+	for i := range k.issues {
+		if k.issues[i].matches(report.FullText(), report.Labels()) {
+			return &k.issues[i]
+		}
+	}
+	return nil
+}
+
+// FailHandler wraps fail (usually ginkgo.Fail) so that the failures of the specs matching a known issue are reported
+// as expected failures. Register it with gomega.RegisterFailHandler.
+func (k *KnownIssues) FailHandler(fail func(message string, callerSkip ...int)) func(message string, callerSkip ...int) {
+	return func(message string, callerSkip ...int) {
+		skip := 1
+		if len(callerSkip) > 0 {
+			skip += callerSkip[0]
+		}
+		issue := k.match()
+		if issue == nil || k.xfailed {
+			fail(message, skip)
+			return
+		}
+		k.xfailed = true
+		ginkgo.Skip(fmt.Sprintf("%s%s: %s", xfailPrefix, issue, message), skip)
+	}
+}
+
+// Register skips the specs matching the known issues that should be skipped, reports the unexpected passes,
+// and summarises the expected failures and the unexpected passes after the suite.
+//
+// It must be called at the top level of the suite, e.g., next to ginkgo.RunSpecs.
+func (k *KnownIssues) Register() {
+	ginkgo.BeforeEach(func() {
+		k.xfailed = false
+		if issue := k.match(); issue != nil && issue.Skip {
+			ginkgo.Skip(fmt.Sprintf("known issue: %s", issue))
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		if k.xfailed || ginkgo.CurrentSpecReport().State != types.SpecStatePassed {
+			return
+		}
+		if issue := k.match(); issue != nil && !issue.Skip {
+			ginkgo.GinkgoWriter.Printf("The spec is unexpectedly passing despite the known issue: %s\n", issue)
+			ginkgo.AddReportEntry(xpassReportName, fmt.Sprintf("unexpectedly passing despite the known issue: %s", issue))
+		}
+	})
+
+	ginkgo.ReportAfterSuite("known issue summary", func(report ginkgo.Report) {
+		if summary := knownIssueSummary(report.SpecReports); summary != "" {
+			ginkgo.GinkgoWriter.Print(summary)
+		}
+	})
+}
+
+// knownIssueSummary lists the specs that failed as expected and the ones that unexpectedly passed.
+func knownIssueSummary(specs types.SpecReports) string {
+	var xfails, xpasses []string
+	for _, spec := range specs {
+		if spec.State == types.SpecStateSkipped && strings.HasPrefix(spec.Failure.Message, xfailPrefix) {
+			xfails = append(xfails, spec.FullText())
+		}
+		for _, entry := range spec.ReportEntries {
+			if entry.Name == xpassReportName {
+				xpasses = append(xpasses, spec.FullText())
+			}
+		}
+	}
+	if len(xfails) == 0 && len(xpasses) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Known issues: %d expected failure(s), %d unexpected pass(es)\n", len(xfails), len(xpasses))
+	for _, s := range xfails {
+		fmt.Fprintf(&b, "  XFAIL %s\n", s)
+	}
+	for _, s := range xpasses {
+		fmt.Fprintf(&b, "  XPASS %s\n", s)
+	}
+	return b.String()
+}
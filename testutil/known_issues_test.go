@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+func TestNewKnownIssues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		issue   KnownIssue
+		wantErr bool
+	}{
+		{name: "Spec", issue: KnownIssue{Spec: "^Run .* --cpus$", Reason: "no cgroup v2"}},
+		{name: "Label", issue: KnownIssue{Label: "compose", Reason: "unsupported"}},
+		{name: "NeitherSpecNorLabel", issue: KnownIssue{Reason: "unsupported"}, wantErr: true},
+		{name: "NoReason", issue: KnownIssue{Label: "compose"}, wantErr: true},
+		{name: "InvalidSpec", issue: KnownIssue{Spec: "(", Reason: "unsupported"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := NewKnownIssues(test.issue); (err != nil) != test.wantErr {
+				t.Fatalf("expected error: %t, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadKnownIssues(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "known-issues.yaml")
+	content := `
+- spec: Run a container image .* --cpus
+  reason: cgroup v2 is not enabled
+  link: https://example.com/issues/1
+- label: compose
+  reason: compose is not supported yet
+  skip: true
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	k, err := LoadKnownIssues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(k.issues) != 2 {
+		t.Fatalf("expected 2 known issues, got %d", len(k.issues))
+	}
+
+	cpus, compose := k.issues[0], k.issues[1]
+	if got, want := cpus.String(), "cgroup v2 is not enabled (https://example.com/issues/1)"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if !cpus.matches("Run a container image should set number of CPUs with --cpus flag", nil) {
+		t.Fatal("expected the spec to match")
+	}
+	if cpus.matches("Run a container image should echo dummy output", nil) {
+		t.Fatal("expected the spec not to match")
+	}
+	if !compose.Skip || !compose.matches("Compose build", []string{"compose"}) || compose.matches("Compose build", nil) {
+		t.Fatal("expected the label to be matched")
+	}
+}
+
+func TestKnownIssueSummary(t *testing.T) {
+	t.Parallel()
+
+	specs := types.SpecReports{
+		{
+			LeafNodeText: "fails",
+			State:        types.SpecStateSkipped,
+			Failure:      types.Failure{Message: "XFAIL: no cgroup v2: expected 1 to equal 2"},
+		},
+		{
+			LeafNodeText:  "passes",
+			State:         types.SpecStatePassed,
+			ReportEntries: types.ReportEntries{{Name: "XPASS"}},
+		},
+		{LeafNodeText: "skipped", State: types.SpecStateSkipped, Failure: types.Failure{Message: "requires cgroup v2"}},
+	}
+
+	want := "Known issues: 1 expected failure(s), 1 unexpected pass(es)\n  XFAIL fails\n  XPASS passes\n"
+	if got := knownIssueSummary(specs); got != want {
+		t.Fatalf("expected summary %q, got %q", want, got)
+	}
+	if got := knownIssueSummary(specs[2:]); got != "" {
+		t.Fatalf("expected no summary, got %q", got)
+	}
+}
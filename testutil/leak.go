@@ -0,0 +1,81 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+)
+
+// LeakCheckMode specifies what happens when a spec leaves objects behind. See CheckLeaks for more details.
+type LeakCheckMode string
+
+const (
+	// LeakCheckOff disables the leak check.
+	LeakCheckOff LeakCheckMode = "off"
+	// LeakCheckReport adds a report entry listing the leaked objects to the spec.
+	LeakCheckReport LeakCheckMode = "report"
+	// LeakCheckFail fails the spec that leaked objects.
+	LeakCheckFail LeakCheckMode = "fail"
+)
+
+// ParseLeakCheckMode parses the leak check mode from s. An empty string means LeakCheckOff.
+func ParseLeakCheckMode(s string) (LeakCheckMode, error) {
+	switch mode := LeakCheckMode(s); mode {
+	case "":
+		return LeakCheckOff, nil
+	case LeakCheckOff, LeakCheckReport, LeakCheckFail:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown leak check mode %q, must be one of %q, %q and %q", s, LeakCheckOff, LeakCheckReport, LeakCheckFail)
+	}
+}
+
+// CheckLeaks checks that every spec removes the containers, images, volumes and networks that it creates.
+//
+// The tests remove everything with command.RemoveAll (or UseSpecNamespace) around each spec, which hides the specs that
+// do not clean up after themselves. When enabled, the objects in the testing environment are recorded before each spec
+// runs (i.e., before any other BeforeEach node) and compared with the ones that exist after it and all of its cleanup
+// have run (i.e., after every AfterEach node and the other cleanup registered with ginkgo.DeferCleanup).
+// From the JustAfterEach nodes on, the cleanup functions in the command package are suspended (see option.Option.SuspendCleanup),
+// so only the objects that the spec removes by itself (e.g., with "rm") are not counted.
+// The new objects are reported according to mode, and are removed afterwards. Specs that already failed are not checked.
+//
+// CheckLeaks must be called at the top level of the spec tree (e.g., right before ginkgo.RunSpecs).
+// Since the objects are not attributed to a spec, the results are only accurate when the specs are not run in parallel.
+func CheckLeaks(o *option.Option, mode LeakCheckMode) {
+	if mode == LeakCheckOff || mode == "" {
+		return
+	}
+
+	ginkgo.BeforeEach(func() {
+		before := command.TakeSnapshot(o)
+		// The cleanup registered here runs after the AfterEach nodes and the cleanup registered later by the spec.
+		ginkgo.DeferCleanup(func() {
+			o.ResumeCleanup()
+			leaked := before.Diff(command.TakeSnapshot(o))
+			if leaked.IsEmpty() {
+				return
+			}
+			command.RemoveSnapshot(o, leaked)
+			if ginkgo.CurrentSpecReport().Failed() {
+				return
+			}
+			switch mode {
+			case LeakCheckFail:
+				ginkgo.Fail(fmt.Sprintf("the spec leaked the following objects:\n%s", leaked))
+			default:
+				ginkgo.GinkgoWriter.Printf("The spec leaked the following objects:\n%s\n", leaked)
+				ginkgo.AddReportEntry("leaked objects", leaked.String())
+			}
+		})
+	})
+
+	// JustAfterEach nodes run before the AfterEach nodes, where the suites usually remove everything.
+	ginkgo.JustAfterEach(o.SuspendCleanup)
+}
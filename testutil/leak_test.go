@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// containers returns the rules of a fake subject that lists the containers
+// and succeeds with no output for the other commands (e.g., "run" and "rm").
+func containers(names ...string) string {
+	var stdout string
+	if len(names) > 0 {
+		stdout = strings.Join(names, `\n`) + `\n`
+	}
+	return fmt.Sprintf(`
+rules:
+  - args: ^ps --all --quiet --no-trunc$
+    stdout: "%s"
+default: {}
+`, stdout)
+}
+
+var _ = ginkgo.Describe("CheckLeaks", func() {
+	o, scenario := newFakeOption("leak-check", containers())
+
+	// update makes the fake subject list the containers that are run and not removed so far, like a real subject would.
+	update := func() {
+		var names []string
+		for _, args := range scenario.invocations() {
+			switch {
+			case len(args) == 4 && args[0] == "run":
+				names = append(names, args[2])
+			case len(args) > 2 && args[0] == "rm":
+				names = slices.DeleteFunc(names, func(name string) bool { return slices.Contains(args[2:], name) })
+			}
+		}
+		scenario.set(containers(names...))
+	}
+
+	runContainer := func(name string) {
+		command.Run(o, "run", "--name", name, "alpine")
+		update()
+	}
+
+	// removeContainer removes the container by itself, i.e., not with the cleanup functions in the command package.
+	removeContainer := func(name string) {
+		command.Run(o, "rm", "--force", name)
+		update()
+	}
+
+	ginkgo.BeforeEach(func() {
+		scenario.reset(containers())
+	})
+
+	ginkgo.When("the leaks fail the specs", func() {
+		testutil.CheckLeaks(o, testutil.LeakCheckFail)
+
+		ginkgo.Context("and the spec removes its container in AfterEach", func() {
+			ginkgo.AfterEach(func() {
+				removeContainer("after-each")
+			})
+
+			ginkgo.It("should not report the container as leaked", func() {
+				runContainer("after-each")
+			})
+		})
+
+		ginkgo.It("should not report the container removed by DeferCleanup as leaked", func() {
+			runContainer("defer-cleanup")
+			ginkgo.DeferCleanup(removeContainer, "defer-cleanup")
+		})
+	})
+
+	ginkgo.When("the leaks are reported", func() {
+		testutil.CheckLeaks(o, testutil.LeakCheckReport)
+
+		ginkgo.ReportAfterEach(func(report ginkgo.SpecReport) {
+			var leaked []string
+			for _, entry := range report.ReportEntries {
+				if entry.Name == "leaked objects" {
+					leaked = append(leaked, entry.StringRepresentation())
+				}
+			}
+			gomega.Expect(leaked).Should(gomega.Equal([]string{"containers: leaked"}))
+			// The leaked container is removed after it's reported.
+			got := scenario.invocations()
+			gomega.Expect(got[len(got)-1]).Should(gomega.Equal([]string{"rm", "--force", "leaked"}))
+		})
+
+		ginkgo.It("should report the container that is not removed", func() {
+			runContainer("leaked")
+		})
+
+		ginkgo.Context("and the suite removes everything in AfterEach", func() {
+			ginkgo.AfterEach(func() {
+				command.RemoveAll(o)
+				update()
+			})
+
+			ginkgo.It("should still report the container that the spec does not remove", func() {
+				runContainer("leaked")
+			})
+		})
+	})
+})
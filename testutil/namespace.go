@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/option"
+)
+
+// NamespaceLabel is the key of the label that UseSpecNamespace adds to the objects created by a spec.
+const NamespaceLabel = "com.github.runfinch.common-tests.namespace"
+
+// SpecNamespace returns a name that is unique to the running spec across all the parallel processes.
+//
+// It is derived from the parallel process number and a hash of the location and the full text of the spec,
+// so it stays the same when the spec is retried. It panics if no spec is running (e.g., while the spec tree is constructed),
+// where all the specs would share the same namespace.
+func SpecNamespace() string {
+	report := ginkgo.CurrentSpecReport()
+	if report.LeafNodeType == types.NodeTypeInvalid {
+		panic("SpecNamespace must be called while a spec is running")
+	}
+	h := sha256.Sum256([]byte(report.LeafNodeLocation.String() + "\n" + report.FullText()))
+	return fmt.Sprintf("p%d-%s", ginkgo.GinkgoParallelProcess(), hex.EncodeToString(h[:])[:12])
+}
+
+// UniqueName returns base suffixed with the namespace of the running spec (see SpecNamespace).
+// Use it instead of a fixed name for the objects created by a spec (e.g., containers and volumes)
+// so that the same spec can run in parallel with the others.
+func UniqueName(base string) string {
+	return fmt.Sprintf("%s-%s", base, SpecNamespace())
+}
+
+// UseSpecNamespace labels the objects created through o by the running spec with its namespace
+// (see SpecNamespace and NamespaceLabel), and removes the labelled objects after the spec finishes,
+// together with the ones named with UniqueName (e.g., the volumes created by "run -v" are not labelled).
+//
+// It is usually invoked in a BeforeEach node and replaces command.RemoveAll,
+// which removes the objects created by the other parallel processes too.
+func UseSpecNamespace(o *option.Option) {
+	namespace := SpecNamespace()
+	o.SetLabel(NamespaceLabel, namespace)
+	ginkgo.DeferCleanup(func() {
+		o.DeleteLabel(NamespaceLabel)
+		command.RemoveAllWithLabel(o, fmt.Sprintf("%s=%s", NamespaceLabel, namespace))
+		command.RemoveAllWithNameContaining(o, namespace)
+	})
+}
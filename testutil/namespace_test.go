@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil_test
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/command"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+var _ = ginkgo.Describe("SpecNamespace", ginkgo.Ordered, func() {
+	var first string
+
+	ginkgo.It("should be stable within the spec", func() {
+		first = testutil.SpecNamespace()
+		gomega.Expect(first).Should(gomega.MatchRegexp(`^p\d+-[0-9a-f]{12}$`))
+		gomega.Expect(testutil.SpecNamespace()).Should(gomega.Equal(first))
+		gomega.Expect(testutil.UniqueName("ctr")).Should(gomega.Equal("ctr-" + first))
+	})
+
+	ginkgo.It("should differ between the specs", func() {
+		gomega.Expect(testutil.SpecNamespace()).ShouldNot(gomega.Equal(first))
+	})
+})
+
+var _ = ginkgo.Describe("UseSpecNamespace", ginkgo.Ordered, func() {
+	o, scenario := newFakeOption("namespace", "")
+	var namespace string
+
+	ginkgo.It("should label the objects created by the spec", func() {
+		namespace = testutil.SpecNamespace()
+		scenario.reset(fmt.Sprintf(`
+rules:
+  - args: --filter label=%[1]s=%[2]s$
+    stdout: "labelled\n"
+  - args: ^ps --all --format
+    stdout: "ctr-%[2]s\nothers\n"
+  - args: ^volume ls --quiet$
+    stdout: "vol-%[2]s\nothers\n"
+default: {}
+`, testutil.NamespaceLabel, namespace))
+		testutil.UseSpecNamespace(o)
+		command.Run(o, "run", "--name", testutil.UniqueName("ctr"), "img")
+		gomega.Expect(scenario.invocations()).Should(gomega.Equal([][]string{
+			{"run", "--label", testutil.NamespaceLabel + "=" + namespace, "--name", "ctr-" + namespace, "img"},
+		}))
+	})
+
+	ginkgo.It("should remove the objects of the spec after it finishes", func() {
+		got := scenario.invocations()
+		gomega.Expect(got).Should(gomega.ContainElements(
+			[]string{"rm", "--force", "labelled"},
+			[]string{"volume", "rm", "--force", "labelled"},
+			[]string{"rm", "--force", "ctr-" + namespace},
+			[]string{"volume", "rm", "--force", "vol-" + namespace},
+		))
+		gomega.Expect(got).ShouldNot(gomega.ContainElement(gomega.ContainElement("others")))
+		gomega.Expect(o.Labels()).Should(gomega.BeEmpty())
+	})
+})
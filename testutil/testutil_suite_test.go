@@ -0,0 +1,105 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/runfinch/common-tests/fake"
+	"github.com/runfinch/common-tests/option"
+)
+
+// TestTestutil runs the specs of the helpers that register nodes in the spec tree (e.g., CheckLeaks).
+func TestTestutil(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Testutil Suite")
+}
+
+// subjectDirs are the temporary directories created by subjectDir.
+var subjectDirs []string
+
+// fakeDir is where the fake subject (see the fake package) is built to and where its scenarios are written.
+var fakeDir = subjectDir("testutil-fake")
+
+var _ = ginkgo.BeforeSuite(func() {
+	path, err := gexec.Build("github.com/runfinch/common-tests/fake/cmd/fake-subject")
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	gomega.Expect(os.Rename(path, fakeSubjectPath())).Should(gomega.Succeed())
+})
+
+var _ = ginkgo.AfterSuite(func() {
+	gexec.CleanupBuildArtifacts()
+	for _, dir := range subjectDirs {
+		gomega.Expect(os.RemoveAll(dir)).Should(gomega.Succeed())
+	}
+})
+
+// subjectDir creates a temporary directory where a fake subject keeps its state, which is removed after the suite.
+// It's created while the spec tree is constructed because the helpers under test (e.g., CheckLeaks) need the subject then,
+// which may be before Gomega's fail handler is registered, so it panics on errors.
+func subjectDir(pattern string) string {
+	dir, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		panic(err)
+	}
+	subjectDirs = append(subjectDirs, dir)
+	return dir
+}
+
+// fakeSubjectPath returns the path of the fake subject, which is fixed before it's built
+// so that the options of the helpers under test can be created while the spec tree is constructed.
+func fakeSubjectPath() string {
+	name := "fake-subject"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(fakeDir, name)
+}
+
+// fakeScenario is the scenario file that drives a fake subject (see fake.Scenario) and the log of its invocations.
+type fakeScenario struct {
+	path string
+	log  string
+}
+
+// newFakeOption returns an option whose subject is the fake subject driven by the scenario named name,
+// which starts with rules (and the default response, if any).
+// The scenario is read on every invocation, so the specs can change it with set to emulate a stateful subject.
+// Like subjectDir, it panics on errors because it may be called before Gomega's fail handler is registered.
+func newFakeOption(name, rules string) (*option.Option, *fakeScenario) {
+	s := &fakeScenario{path: filepath.Join(fakeDir, name+".yaml"), log: filepath.Join(fakeDir, name+".jsonl")}
+	if err := os.WriteFile(s.path, []byte("log: "+s.log+"\n"+rules), 0o600); err != nil {
+		panic(err)
+	}
+	o, err := option.New([]string{fakeSubjectPath(), s.path})
+	if err != nil {
+		panic(err)
+	}
+	return o, s
+}
+
+// set replaces the rules (and the default response, if any) of the scenario.
+func (s *fakeScenario) set(rules string) {
+	gomega.Expect(os.WriteFile(s.path, []byte("log: "+s.log+"\n"+rules), 0o600)).Should(gomega.Succeed())
+}
+
+// reset replaces the rules of the scenario and forgets the invocations so far.
+func (s *fakeScenario) reset(rules string) {
+	gomega.Expect(os.RemoveAll(s.log)).Should(gomega.Succeed())
+	s.set(rules)
+}
+
+// invocations returns the arguments of the invocations of the fake subject.
+func (s *fakeScenario) invocations() [][]string {
+	got, err := fake.Invocations(s.log)
+	gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+	return got
+}
@@ -6,31 +6,154 @@ package testutil
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
 
 	"github.com/runfinch/common-tests/option"
 )
 
+// componentVersionLabelPrefix is the prefix of the labels created by RequiresComponentVersion.
+const componentVersionLabelPrefix = "requires-version:"
+
+// componentVersionSkipRegex matches the messages of the specs skipped by RequireComponentVersion.
+var componentVersionSkipRegex = regexp.MustCompile(`^(\S+) version (.+) does not satisfy constraint (.+)$`)
+
+var (
+	versionsMu sync.Mutex
+	versions   = map[*option.Option]componentVersions{}
+)
+
+// componentVersions are the versions of the components reported by a subject, or why they could not be got.
+type componentVersions struct {
+	versions map[string]string
+	err      error
+}
+
+// getComponentVersions returns the versions of the components reported by the subject of o
+// (see option.Option.GetComponentVersions). The subject is only asked once per option,
+// and the result is shared by the version gates and ProbeCapabilities, so a version gate does not probe the other capabilities.
+func getComponentVersions(o *option.Option) (map[string]string, error) {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+	if v, ok := versions[o]; ok {
+		return v.versions, v.err
+	}
+	v, err := o.GetComponentVersions()
+	if err != nil {
+		ginkgo.GinkgoWriter.Printf("Failed to get the versions of the components: %v\n", err)
+	}
+	versions[o] = componentVersions{versions: v, err: err}
+	return v, err
+}
+
 // RequireNerdctlVersion skips a test if the nerdctl version does not satisfy the constraint.
 func RequireNerdctlVersion(o *option.Option, constraint string) {
+	RequireComponentVersion(o, option.ComponentNerdctl, constraint)
+}
+
+// RequireComponentVersion skips a test if the version of the component (e.g., option.ComponentContainerd)
+// does not satisfy the constraint, or if the subject does not report the version of the component
+// (see option.Option.GetComponentVersions). The versions are only got from the subject once per option.
+func RequireComponentVersion(o *option.Option, component, constraint string) {
 	c, err := semver.NewConstraint(constraint)
 	if err != nil {
 		ginkgo.Fail(fmt.Sprintf("failed to construct constraint from %s: %s", constraint, err.Error()))
 		return
 	}
-	nerdctlVersion, err := o.GetNerdctlVersion()
+	versions, err := getComponentVersions(o)
 	if err != nil {
-		ginkgo.Fail(fmt.Sprintf("failed to get nerdctl version: %s", err.Error()))
+		ginkgo.Fail(fmt.Sprintf("failed to get %s version: %s", component, err.Error()))
+		return
+	}
+	version, ok := versions[component]
+	if !ok {
+		ginkgo.Skip(fmt.Sprintf("%s version unknown does not satisfy constraint %s", component, constraint))
 		return
 	}
-	v, err := semver.NewVersion(nerdctlVersion)
+	v, err := semver.NewVersion(version)
 	if err != nil {
-		ginkgo.Fail(fmt.Sprintf("failed to construct semver from %s: %s", nerdctlVersion, err.Error()))
+		ginkgo.Fail(fmt.Sprintf("failed to construct semver from %s: %s", version, err.Error()))
 		return
 	}
 	if !c.Check(v) {
-		ginkgo.Skip(fmt.Sprintf("nerdctl version %s does not satisfy constraint %s", v, constraint))
+		ginkgo.Skip(fmt.Sprintf("%s version %s does not satisfy constraint %s", component, v, constraint))
+	}
+}
+
+// RequiresComponentVersion is a Ginkgo decorator that does the same gating as RequireComponentVersion declaratively,
+// e.g., ginkgo.It("...", testutil.RequiresComponentVersion(option.ComponentContainerd, ">= 1.7"), func() {...}).
+// It only takes effect if GateComponentVersions is used.
+//
+// The constraint is stored in a label, which must not contain the characters forbidden by Ginkgo (e.g., "," and "|").
+func RequiresComponentVersion(component, constraint string) ginkgo.Labels {
+	return ginkgo.Label(componentVersionLabelPrefix + component + " " + constraint)
+}
+
+// GateComponentVersions skips the specs decorated with RequiresComponentVersion whose constraints are not satisfied,
+// and summarises the specs skipped by RequireComponentVersion by component after the suite.
+// The summary is added as a report entry that is always printed, i.e., even without -v.
+//
+// It must be called at the top level of the suite, e.g., next to ginkgo.RunSpecs.
+func GateComponentVersions(o *option.Option) {
+	ginkgo.BeforeEach(func() {
+		for _, label := range ginkgo.CurrentSpecReport().Labels() {
+			spec, found := strings.CutPrefix(label, componentVersionLabelPrefix)
+			if !found {
+				continue
+			}
+			component, constraint, _ := strings.Cut(spec, " ")
+			RequireComponentVersion(o, component, constraint)
+		}
+	})
+
+	ginkgo.ReportAfterSuite("component version summary", func(report ginkgo.Report) {
+		if summary := componentVersionSummary(report.SpecReports); summary != "" {
+			ginkgo.AddReportEntry("component version summary", summary, ginkgo.ReportEntryVisibilityAlways)
+		}
+	})
+}
+
+// componentVersionSummary returns how many specs are skipped because of the version of each component and why.
+func componentVersionSummary(specs types.SpecReports) string {
+	skips := map[string]map[string]int{}
+	for _, spec := range specs {
+		if spec.State != types.SpecStateSkipped {
+			continue
+		}
+		m := componentVersionSkipRegex.FindStringSubmatch(spec.Failure.Message)
+		if m == nil {
+			continue
+		}
+		if skips[m[1]] == nil {
+			skips[m[1]] = map[string]int{}
+		}
+		skips[m[1]][fmt.Sprintf("version %s does not satisfy constraint %s", m[2], m[3])]++
+	}
+	if len(skips) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Specs skipped because of component versions:\n")
+	for _, component := range sortedKeys(skips) {
+		for _, reason := range sortedKeys(skips[component]) {
+			fmt.Fprintf(&b, "  %s: %d spec(s), %s\n", component, skips[component][reason], reason)
+		}
+	}
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+	return keys
 }
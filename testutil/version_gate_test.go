@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/onsi/gomega"
+
+	"github.com/runfinch/common-tests/option"
+	"github.com/runfinch/common-tests/testutil"
+)
+
+// versionedOpt is an option whose subject reports nerdctl 1.7.0 and containerd 1.6.0
+// with `version --format "{{json .}}"` and fails the other commands.
+var versionedOpt, versionedScenario = newFakeOption("version-gate", `
+rules:
+  - args: ^version --format \{\{json \.\}\}$
+    stdout: '{"Client":{"Version":"v1.7.0"},"Server":{"Components":[{"Name":"containerd","Version":"v1.6.0"}]}}'
+default:
+  exitCode: 1
+`)
+
+var _ = func() bool {
+	testutil.GateComponentVersions(versionedOpt)
+	return true
+}()
+
+var _ = ginkgo.Describe("GateComponentVersions", ginkgo.Ordered, func() {
+	// shouldEnd checks the state of the spec, and the reason it's skipped if skip is not empty.
+	shouldEnd := func(report ginkgo.SpecReport, skip string) {
+		if skip == "" {
+			gomega.Expect(report.State).Should(gomega.Equal(types.SpecStatePassed))
+			return
+		}
+		gomega.Expect(report.State).Should(gomega.Equal(types.SpecStateSkipped))
+		gomega.Expect(report.Failure.Message).Should(gomega.Equal(skip))
+	}
+
+	ginkgo.Context("when the constraint is satisfied", func() {
+		ginkgo.ReportAfterEach(func(report ginkgo.SpecReport) {
+			shouldEnd(report, "")
+		})
+
+		ginkgo.It("should run the spec", testutil.RequiresComponentVersion(option.ComponentContainerd, ">= 1.6"), func() {})
+	})
+
+	ginkgo.Context("when the constraint is not satisfied", func() {
+		ginkgo.ReportAfterEach(func(report ginkgo.SpecReport) {
+			shouldEnd(report, "containerd version 1.6.0 does not satisfy constraint >= 1.7")
+		})
+
+		ginkgo.It("should skip the spec", testutil.RequiresComponentVersion(option.ComponentContainerd, ">= 1.7"), func() {})
+	})
+
+	ginkgo.Context("when the subject does not report the version", func() {
+		ginkgo.ReportAfterEach(func(report ginkgo.SpecReport) {
+			shouldEnd(report, "cni version unknown does not satisfy constraint >= 1.0")
+		})
+
+		ginkgo.It("should skip the spec", testutil.RequiresComponentVersion(option.ComponentCNI, ">= 1.0"), func() {})
+	})
+
+	ginkgo.It("should only get the versions from the subject once without probing the other capabilities", func() {
+		testutil.RequireNerdctlVersion(versionedOpt, ">= 1.7")
+		// See option.Option.GetComponentVersions for the commands that the versions are got from.
+		gomega.Expect(versionedScenario.invocations()).Should(gomega.Equal([][]string{
+			{"version", "--format", "{{json .}}"},
+			{"compose", "version", "--format", "json"},
+		}))
+	})
+})
@@ -0,0 +1,37 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+func TestComponentVersionSummary(t *testing.T) {
+	t.Parallel()
+
+	skipped := func(message string) types.SpecReport {
+		return types.SpecReport{State: types.SpecStateSkipped, Failure: types.Failure{Message: message}}
+	}
+	specs := types.SpecReports{
+		skipped("runc version 1.1.0 does not satisfy constraint >= 1.2"),
+		skipped("containerd version 1.6.0 does not satisfy constraint >= 1.7"),
+		skipped("containerd version 1.6.0 does not satisfy constraint >= 1.7"),
+		skipped("cni version unknown does not satisfy constraint >= 1.0"),
+		skipped("requires cgroup v2"),
+		{State: types.SpecStatePassed},
+	}
+
+	want := "Specs skipped because of component versions:\n" +
+		"  cni: 1 spec(s), version unknown does not satisfy constraint >= 1.0\n" +
+		"  containerd: 2 spec(s), version 1.6.0 does not satisfy constraint >= 1.7\n" +
+		"  runc: 1 spec(s), version 1.1.0 does not satisfy constraint >= 1.2\n"
+	if got := componentVersionSummary(specs); got != want {
+		t.Fatalf("expected summary %q, got %q", want, got)
+	}
+	if got := componentVersionSummary(types.SpecReports{skipped("requires cgroup v2")}); got != "" {
+		t.Fatalf("expected no summary, got %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package transcript reads and writes the transcripts of the commands run during testing.
+//
+// A transcript is a JSONL file with one Record per line. It's written by the command package when
+// option.WithTranscript is used, and can be replayed by the fake subject (see the fake package) to debug a failure offline.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Record is a single invocation of the test subject.
+type Record struct {
+	// Subject is the subject that the command was run with (see option.New).
+	Subject []string `json:"subject"`
+	// Args are the arguments passed to the subject.
+	Args []string `json:"args"`
+	// Env are the environment variables set by the option on top of the ones inherited from the test process.
+	Env []string `json:"env,omitempty"`
+	// StdinSHA256 is the hex-encoded SHA-256 of the stdin read by the command, if any.
+	StdinSHA256 string `json:"stdinSha256,omitempty"`
+	Stdout      string `json:"stdout"`
+	Stderr      string `json:"stderr"`
+	// ExitCode is -1 if the command was killed.
+	ExitCode int           `json:"exitCode"`
+	Duration time.Duration `json:"duration"`
+}
+
+var mu sync.Mutex
+
+// Append appends r to the transcript at path, creating the file if needed.
+func Append(path string, r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	f, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the transcript: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write the transcript: %w", err)
+	}
+	return f.Close()
+}
+
+// Read reads all the records in the transcript at path.
+func Read(path string) ([]Record, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the transcript: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // The file is only read.
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("failed to parse line %d of the transcript: %w", line, err)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// Find returns the nth (0-based) record whose arguments match args (see Match).
+// If there are fewer such records, the last one is returned, since a command is usually retried until it succeeds.
+func Find(records []Record, args []string, n int) (Record, bool) {
+	var found []Record
+	for _, r := range records {
+		if Match(r.Args, args) {
+			found = append(found, r)
+		}
+	}
+	if len(found) == 0 {
+		return Record{}, false
+	}
+	return found[min(n, len(found)-1)], true
+}
+
+var (
+	// tempPathRe matches an absolute path to a file or directory created with os.MkdirTemp (e.g., by the ffs package),
+	// whose last element before the random suffix is captured. The parent directory (e.g., the home directory) is not
+	// captured because it usually differs between the host that records a transcript and the one that replays it.
+	tempPathRe = regexp.MustCompile(`(?:[A-Za-z]:)?[^\s:=,]*[/\\]([A-Za-z][\w.-]*?)\d{6,10}\b`)
+	// tempNameRe matches a name generated from a temporary directory (e.g., a compose project), whose prefix is captured.
+	tempNameRe = regexp.MustCompile(`\b([A-Za-z][\w.-]*?[A-Za-z_.-])\d{6,10}\b`)
+	// leadingPortRe and hostPortRe match the ports with 4 or 5 digits (e.g., the ones returned by fnet.GetFreePort)
+	// at the start of a port mapping (e.g., 8080:80) and after a host (e.g., localhost:5000/alpine) respectively.
+	// The container port of a port mapping is not matched because it does not change.
+	leadingPortRe = regexp.MustCompile(`^\d{4,5}:`)
+	hostPortRe    = regexp.MustCompile(`([\w.\]-]):\d{4,5}\b`)
+)
+
+// Normalize replaces the parts of args that change from run to run with placeholders:
+// the paths and the names derived from temporary directories (e.g., /home/user/finch-test123456789/Dockerfile
+// becomes <tmp>/finch-test*/Dockerfile), and the ports (e.g., localhost:41234/alpine becomes localhost:<port>/alpine).
+func Normalize(args []string) []string {
+	normalized := make([]string, 0, len(args))
+	for _, arg := range args {
+		arg = tempPathRe.ReplaceAllString(arg, "<tmp>/${1}*")
+		arg = tempNameRe.ReplaceAllString(arg, "${1}*")
+		arg = leadingPortRe.ReplaceAllString(arg, "<port>:")
+		arg = hostPortRe.ReplaceAllString(arg, "${1}:<port>")
+		normalized = append(normalized, arg)
+	}
+	return normalized
+}
+
+// Match returns true if the recorded arguments and args are the same after they are normalized (see Normalize).
+func Match(recorded, args []string) bool {
+	return slices.Equal(Normalize(recorded), Normalize(args))
+}
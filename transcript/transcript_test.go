@@ -0,0 +1,120 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package transcript
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	want := []Record{
+		{Subject: []string{"nerdctl"}, Args: []string{"ps", "-q"}, Stdout: "id\n", Duration: time.Second},
+		{Subject: []string{"nerdctl"}, Args: []string{"pull", "x"}, Env: []string{"A=b"}, StdinSHA256: "abc", Stderr: "oops", ExitCode: 1},
+	}
+	for _, r := range want {
+		if err := Append(path, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	records := []Record{
+		{Args: []string{"pull", "x"}, ExitCode: 1},
+		{Args: []string{"ps"}},
+		{Args: []string{"pull", "x"}, ExitCode: 0},
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		n        int
+		wantOK   bool
+		wantExit int
+	}{
+		{name: "First", args: []string{"pull", "x"}, n: 0, wantOK: true, wantExit: 1},
+		{name: "Second", args: []string{"pull", "x"}, n: 1, wantOK: true, wantExit: 0},
+		{name: "BeyondTheLast", args: []string{"pull", "x"}, n: 5, wantOK: true, wantExit: 0},
+		{name: "NotFound", args: []string{"pull"}, n: 0, wantOK: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, ok := Find(records, test.args, test.n)
+			if ok != test.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, test.wantOK)
+			}
+			if ok && r.ExitCode != test.wantExit {
+				t.Fatalf("got exit code %d, want %d", r.ExitCode, test.wantExit)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "Ports",
+			args: []string{"run", "-d", "-p", "41234:5000", "-p", "127.0.0.1:8080:80", "localhost:41234/alpine:latest"},
+			want: []string{"run", "-d", "-p", "<port>:5000", "-p", "127.0.0.1:<port>:80", "localhost:<port>/alpine:latest"},
+		},
+		{
+			name: "TempPaths",
+			args: []string{"build", "-f", "/home/ci/finch-test123456789/Dockerfile", "/home/ci/finch-test123456789"},
+			want: []string{"build", "-f", "<tmp>/finch-test*/Dockerfile", "<tmp>/finch-test*"},
+		},
+		{
+			name: "TempPathsInFlags",
+			args: []string{"run", "--env-file=/Users/dev/finch-test42424242/env", "-v", "/home/ci/finch-test-cid1234567:/data"},
+			want: []string{"run", "--env-file=<tmp>/finch-test*/env", "-v", "<tmp>/finch-test-cid*:/data"},
+		},
+		{
+			name: "GeneratedNames",
+			args: []string{"rm", "-f", "finch-compose987654321-svc-1"},
+			want: []string{"rm", "-f", "finch-compose*-svc-1"},
+		},
+		{
+			name: "Unchanged",
+			args: []string{"run", "--name", "ctr_1", "-p", "80:80", "alpine", "sleep", "infinity", "sha256:1234567abcdef"},
+			want: []string{"run", "--name", "ctr_1", "-p", "80:80", "alpine", "sleep", "infinity", "sha256:1234567abcdef"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Normalize(test.args); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	if !Match([]string{"push", "localhost:41234/alpine"}, []string{"push", "localhost:39999/alpine"}) {
+		t.Fatal("expected the arguments that only differ in the port to match")
+	}
+	if Match([]string{"push", "localhost:41234/alpine"}, []string{"push", "localhost:41234/busybox"}) {
+		t.Fatal("expected different images not to match")
+	}
+}